@@ -1,39 +1,482 @@
--- 1. Tabel Categories
+-- 1. Tabel Tax Rates (PPN dan sejenisnya, rate dalam persen)
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rate NUMERIC(5, 2) NOT NULL,
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Cuma boleh ada satu tarif default
+CREATE UNIQUE INDEX IF NOT EXISTS tax_rates_single_default ON tax_rates (is_default) WHERE is_default;
+
+-- 2. Tabel Categories
 CREATE TABLE IF NOT EXISTS categories (
     id SERIAL PRIMARY KEY,
     name VARCHAR(100) NOT NULL,
-    description TEXT
+    description TEXT,
+    tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL
+);
+
+-- 3. Tabel Outlets (cabang toko atau gudang, transaksi dan stock dicatat per outlet).
+-- Outlet is_transit itu lokasi transit tersembunyi buat barang transfer yang udah dikirim tapi belum diterima,
+-- nggak muncul di API outlet dan nggak bisa dipakai checkout, opname, adjustment atau purchase order
+CREATE TABLE IF NOT EXISTS outlets (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    is_transit BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK (NOT (is_default AND is_transit))
 );
 
--- 2. Tabel Products
+-- Cuma boleh ada satu outlet default dan satu lokasi transit
+CREATE UNIQUE INDEX IF NOT EXISTS outlets_single_default ON outlets (is_default) WHERE is_default;
+CREATE UNIQUE INDEX IF NOT EXISTS outlets_single_transit ON outlets (is_transit) WHERE is_transit;
+
+-- 4. Tabel Products. Varian (ukuran, warna) disimpan sebagai produk juga dengan parent_id ke produk induknya,
+-- jadi SKU, harga, stock dan ledger-nya per varian. Induk yang punya varian stock-nya selalu 0.
+-- stock itu total semua outlet, stock per outlet ada di outlet_stocks.
+-- Stock, price dan cost_price dalam satuan dasar (base_unit), misalnya pcs atau gram
 CREATE TABLE IF NOT EXISTS products (
     id SERIAL PRIMARY KEY,
+    parent_id INT REFERENCES products(id),
     name VARCHAR(255) NOT NULL,
+    sku VARCHAR(64) UNIQUE,
     price INT NOT NULL,
+    cost_price INT NOT NULL DEFAULT 0,
     stock INT NOT NULL DEFAULT 0,
-    category_id INT REFERENCES categories(id) ON DELETE SET NULL
+    min_stock INT NOT NULL DEFAULT 0,
+    reorder_quantity INT NOT NULL DEFAULT 0,
+    category_id INT REFERENCES categories(id) ON DELETE SET NULL,
+    tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL,
+    tax_exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    variant_attributes TEXT[],
+    variant_name VARCHAR(255),
+    attributes JSONB,
+    price_override BOOLEAN NOT NULL DEFAULT FALSE,
+    is_bundle BOOLEAN NOT NULL DEFAULT FALSE,
+    base_unit VARCHAR(20) NOT NULL DEFAULT 'pcs',
+    track_lots BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS idx_products_parent_id ON products (parent_id);
+
+-- 5. Tabel Product Units (satuan lain selain satuan dasar, misalnya 1 box = 24 pcs atau 1 kg = 1000 gram).
+-- price itu harga jual per satuan ini, 0 berarti harga satuan dasar dikali factor
+CREATE TABLE IF NOT EXISTS product_units (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    factor INT NOT NULL CHECK (factor > 0),
+    price INT NOT NULL DEFAULT 0,
+    UNIQUE (product_id, name)
+);
+
+-- 6. Tabel Bundle Components (isi paket, stock paket ngikut stock komponennya)
+CREATE TABLE IF NOT EXISTS bundle_components (
+    id SERIAL PRIMARY KEY,
+    bundle_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    component_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    UNIQUE (bundle_id, component_id)
+);
+
+-- 7. Tabel Product Barcodes (EAN-13, UPC-A disimpan sebagai EAN-13 dengan awalan 0)
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    barcode VARCHAR(13) NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product_id ON product_barcodes (product_id);
+
+-- 8. Tabel Outlet Stocks (stock produk per outlet, jumlah semua outlet termasuk lokasi transit selalu sama dengan products.stock)
+CREATE TABLE IF NOT EXISTS outlet_stocks (
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    stock INT NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    PRIMARY KEY (outlet_id, product_id)
+);
+CREATE INDEX IF NOT EXISTS idx_outlet_stocks_product_id ON outlet_stocks (product_id);
+
+-- 9. Tabel Stock Lots (stock per batch dan tanggal kadaluarsa buat produk track_lots, per outlet).
+-- Jumlah quantity semua lot satu produk di satu outlet selalu sama dengan outlet_stocks.stock
+CREATE TABLE IF NOT EXISTS stock_lots (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    outlet_id INT NOT NULL REFERENCES outlets(id),
+    batch_number VARCHAR(64) NOT NULL DEFAULT '',
+    expiry_date DATE,
+    quantity INT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_stock_lots_product_expiry ON stock_lots (product_id, outlet_id, expiry_date);
+
+-- 10. Tabel Customers (member toko, phone disimpan tanpa spasi/strip dan diawali 0).
+-- points itu saldo poin loyalty, selalu sama dengan jumlah remaining di loyalty_point_entries
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    member_code VARCHAR(32) NOT NULL UNIQUE,
+    points INT NOT NULL DEFAULT 0 CHECK (points >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS customers_phone ON customers (phone);
+
+-- 11. Tabel Gift Cards (gift card dan voucher store credit, code-nya acak dari crypto/rand).
+-- balance itu sisa saldo, selalu sama dengan jumlah amount di gift_card_entries
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    initial_amount INT NOT NULL CHECK (initial_amount > 0),
+    balance INT NOT NULL CHECK (balance >= 0),
+    note TEXT,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
--- 3. Tabel Transactions
+-- 12. Tabel Transactions
 CREATE TABLE IF NOT EXISTS transactions (
     id SERIAL PRIMARY KEY,
+    outlet_id INT REFERENCES outlets(id),
+    customer_id INT REFERENCES customers(id),
+    gross_amount INT NOT NULL DEFAULT 0,
+    discount_amount INT NOT NULL DEFAULT 0,
+    subtotal_amount INT NOT NULL DEFAULT 0,
+    tax_amount INT NOT NULL DEFAULT 0,
+    service_charge_amount INT NOT NULL DEFAULT 0,
     total_amount INT NOT NULL,
+    paid_amount INT NOT NULL DEFAULT 0,
+    change_amount INT NOT NULL DEFAULT 0,
+    points_redeemed INT NOT NULL DEFAULT 0,
+    points_discount INT NOT NULL DEFAULT 0,
+    points_earned INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'completed',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+CREATE INDEX IF NOT EXISTS idx_transactions_outlet_id ON transactions (outlet_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_customer_id ON transactions (customer_id, created_at);
 
--- 4. Tabel Transaction Details
+-- 13. Tabel Transaction Details
 CREATE TABLE IF NOT EXISTS transaction_details (
     id SERIAL PRIMARY KEY,
     transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
     product_id INT REFERENCES products(id),
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    sku VARCHAR(64) NOT NULL DEFAULT '',
+    parent_product_id INT,
+    parent_product_name VARCHAR(255) NOT NULL DEFAULT '',
+    category_id INT,
+    category_name VARCHAR(100) NOT NULL DEFAULT '',
+    unit_price INT NOT NULL DEFAULT 0,
+    quantity INT NOT NULL,
+    gross_subtotal INT NOT NULL DEFAULT 0,
+    discount_amount INT NOT NULL DEFAULT 0,
+    promotion_id INT,
+    subtotal INT NOT NULL,
+    tax_rate_id INT,
+    tax_rate NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    tax_amount INT NOT NULL DEFAULT 0,
+    service_charge_amount INT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    unit_cost INT NOT NULL DEFAULT 0,
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    unit_quantity NUMERIC(12, 3) NOT NULL DEFAULT 0
+);
+
+-- 14. Tabel Transaction Detail Components (snapshot isi paket waktu checkout, quantity itu jumlah per paket)
+CREATE TABLE IF NOT EXISTS transaction_detail_components (
+    id SERIAL PRIMARY KEY,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id INT REFERENCES products(id),
+    product_name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL,
+    unit_cost INT NOT NULL DEFAULT 0
+);
+
+-- 15. Tabel Transaction Payments (satu transaksi bisa dibayar pakai beberapa metode)
+CREATE TABLE IF NOT EXISTS transaction_payments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    method VARCHAR(20) NOT NULL,
+    amount INT NOT NULL,
+    tendered INT NOT NULL,
+    gift_card_id INT REFERENCES gift_cards(id)
+);
+
+-- 16. Tabel Refunds (void atau refund yang nyambung ke transaksi asal)
+CREATE TABLE IF NOT EXISTS refunds (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    total_amount INT NOT NULL,
+    points_restored INT NOT NULL DEFAULT 0,
+    points_clawed_back INT NOT NULL DEFAULT 0,
+    gift_card_refunded INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 17. Tabel Refund Details
+CREATE TABLE IF NOT EXISTS refund_details (
+    id SERIAL PRIMARY KEY,
+    refund_id INT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+    transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+    product_id INT REFERENCES products(id),
+    quantity INT NOT NULL,
+    amount INT NOT NULL,
+    tax_amount INT NOT NULL DEFAULT 0,
+    service_charge_amount INT NOT NULL DEFAULT 0
+);
+
+-- 18. Tabel Loyalty Point Entries (ledger poin customer, cuma di-insert kecuali remaining).
+-- Baris poin masuk (earn, restore) nyimpen remaining, sisa poin yang belum kepake atau kadaluarsa.
+-- Poin keluar (redeem, clawback, expire) ngurangin remaining baris yang paling cepat kadaluarsa duluan
+CREATE TABLE IF NOT EXISTS loyalty_point_entries (
+    id SERIAL PRIMARY KEY,
+    customer_id INT NOT NULL REFERENCES customers(id),
+    points INT NOT NULL,
+    balance_after INT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    transaction_id INT REFERENCES transactions(id),
+    refund_id INT REFERENCES refunds(id),
+    remaining INT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_loyalty_point_entries_customer ON loyalty_point_entries (customer_id, id);
+CREATE INDEX IF NOT EXISTS idx_loyalty_point_entries_expiry ON loyalty_point_entries (expires_at) WHERE remaining > 0;
+
+-- 19. Tabel Gift Card Entries (ledger saldo gift card: issue, redeem dan refund, cuma di-insert, amount bertanda)
+CREATE TABLE IF NOT EXISTS gift_card_entries (
+    id SERIAL PRIMARY KEY,
+    gift_card_id INT NOT NULL REFERENCES gift_cards(id),
+    amount INT NOT NULL,
+    balance_after INT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    transaction_id INT REFERENCES transactions(id),
+    refund_id INT REFERENCES refunds(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_gift_card_entries_gift_card ON gift_card_entries (gift_card_id, id);
+
+-- 20. Tabel Idempotency Keys (biar retry checkout nggak bikin transaksi dobel)
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
+    response_body TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 21. Tabel Promotions
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    product_id INT REFERENCES products(id) ON DELETE CASCADE,
+    category_id INT REFERENCES categories(id) ON DELETE CASCADE,
+    value INT NOT NULL DEFAULT 0,
+    buy_quantity INT NOT NULL DEFAULT 0,
+    get_quantity INT NOT NULL DEFAULT 0,
+    min_spend INT NOT NULL DEFAULT 0,
+    max_discount INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+-- 22. Tabel Transaction Promotions (promo yang kepake per transaksi)
+CREATE TABLE IF NOT EXISTS transaction_promotions (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    promotion_id INT REFERENCES promotions(id) ON DELETE SET NULL,
+    promotion_name VARCHAR(255) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    amount INT NOT NULL
+);
+
+-- 23. Tabel Stock Movements (ledger stock, cuma di-insert, nggak pernah di-update atau dihapus).
+-- stock_after itu total semua outlet, outlet_stock_after stock di outlet yang berubah.
+-- Foreign key-nya sengaja tanpa CASCADE, produk yang udah punya riwayat stock nggak bisa dihapus
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id),
+    outlet_id INT REFERENCES outlets(id),
+    quantity INT NOT NULL,
+    stock_after INT NOT NULL,
+    outlet_stock_after INT,
+    reason VARCHAR(20) NOT NULL,
+    reference_type VARCHAR(30),
+    reference_id INT,
+    note TEXT,
+    created_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_id ON stock_movements (product_id, id);
+
+-- 24. Tabel Stock Movement Lots (lot mana aja yang berubah di satu baris ledger, quantity bertanda)
+CREATE TABLE IF NOT EXISTS stock_movement_lots (
+    id SERIAL PRIMARY KEY,
+    stock_movement_id INT NOT NULL REFERENCES stock_movements(id),
+    lot_id INT NOT NULL REFERENCES stock_lots(id),
+    quantity INT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_stock_movement_lots_movement ON stock_movement_lots (stock_movement_id);
+
+-- 25. Tabel Low Stock Alerts (outbox alert stock menipis). Di-insert di DB transaction yang sama dengan
+-- pergerakan stock yang bikin produk turun melewati min_stock, notified_at diisi setelah notifikasinya terkirim
+CREATE TABLE IF NOT EXISTS low_stock_alerts (
+    stock_movement_id INT PRIMARY KEY REFERENCES stock_movements(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    notified_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_low_stock_alerts_pending ON low_stock_alerts (stock_movement_id) WHERE notified_at IS NULL;
+
+-- 26. Tabel Stock Adjustments (koreksi stock manual: rusak, hilang, kadaluarsa, ketemu, salah hitung).
+-- Sama kayak ledger, foreign key-nya tanpa CASCADE biar catatan koreksi nggak ikut kehapus bareng produknya
+CREATE TABLE IF NOT EXISTS stock_adjustments (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products(id),
+    outlet_id INT REFERENCES outlets(id),
+    quantity INT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 27. Tabel Stock Opnames (sesi hitung fisik stock)
+CREATE TABLE IF NOT EXISTS stock_opnames (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    outlet_id INT REFERENCES outlets(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_by VARCHAR(100) NOT NULL,
+    finalized_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finalized_at TIMESTAMP
+);
+
+-- 28. Tabel Stock Opname Items (system_stock itu stock outlet waktu hitungan disimpan, unit_price diisi waktu finalize)
+CREATE TABLE IF NOT EXISTS stock_opname_items (
+    id SERIAL PRIMARY KEY,
+    stock_opname_id INT NOT NULL REFERENCES stock_opnames(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    counted_quantity INT NOT NULL,
+    counted_by VARCHAR(100) NOT NULL,
+    counted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    system_stock INT,
+    unit_price INT,
+    UNIQUE (stock_opname_id, product_id)
+);
+
+-- 29. Tabel Suppliers
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    address TEXT
+);
+
+-- 30. Tabel Purchase Orders (draft, sent, partially_received, received, cancelled)
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    supplier_id INT NOT NULL REFERENCES suppliers(id),
+    outlet_id INT REFERENCES outlets(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 31. Tabel Purchase Order Items
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL,
+    unit_cost INT NOT NULL,
+    received_quantity INT NOT NULL DEFAULT 0
+);
+
+-- 32. Tabel Goods Receipts (penerimaan barang dari purchase order)
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    received_by VARCHAR(100) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 33. Tabel Goods Receipt Items (quantity dan unit_cost dalam satuan dasar, unit dan unit_quantity satuan waktu diterima)
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id SERIAL PRIMARY KEY,
+    goods_receipt_id INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+    purchase_order_item_id INT NOT NULL REFERENCES purchase_order_items(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
     quantity INT NOT NULL,
-    subtotal INT NOT NULL
+    unit_cost INT NOT NULL,
+    unit VARCHAR(20) NOT NULL DEFAULT '',
+    unit_quantity NUMERIC(12, 3) NOT NULL DEFAULT 0,
+    batch_number VARCHAR(64) NOT NULL DEFAULT '',
+    expiry_date DATE
+);
+
+-- 34. Tabel Stock Transfers (pindah stock antar outlet: requested, shipped, received, cancelled)
+CREATE TABLE IF NOT EXISTS stock_transfers (
+    id SERIAL PRIMARY KEY,
+    from_outlet_id INT NOT NULL REFERENCES outlets(id),
+    to_outlet_id INT NOT NULL REFERENCES outlets(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    note TEXT NOT NULL DEFAULT '',
+    requested_by VARCHAR(100) NOT NULL,
+    shipped_by VARCHAR(100),
+    received_by VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    shipped_at TIMESTAMP,
+    received_at TIMESTAMP,
+    CHECK (from_outlet_id <> to_outlet_id)
+);
+
+-- 35. Tabel Stock Transfer Items
+CREATE TABLE IF NOT EXISTS stock_transfer_items (
+    id SERIAL PRIMARY KEY,
+    stock_transfer_id INT NOT NULL REFERENCES stock_transfers(id) ON DELETE CASCADE,
+    product_id INT NOT NULL REFERENCES products(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    UNIQUE (stock_transfer_id, product_id)
+);
+
+-- 36. Tabel Stock Transfer Item Lots (batch yang dikirim buat produk track_lots, diterima ke batch yang sama)
+CREATE TABLE IF NOT EXISTS stock_transfer_item_lots (
+    id SERIAL PRIMARY KEY,
+    stock_transfer_item_id INT NOT NULL REFERENCES stock_transfer_items(id) ON DELETE CASCADE,
+    batch_number VARCHAR(64) NOT NULL DEFAULT '',
+    expiry_date DATE,
+    quantity INT NOT NULL
 );
 
 -- ================================================
 -- Seed Data
 -- ================================================
 
+-- Insert Tax Rates (belum ada yang default, set is_default biar semua produk kena PPN)
+INSERT INTO tax_rates (name, rate, inclusive) VALUES
+('PPN 11%', 11, FALSE);
+
+-- Insert Outlets (stock awal semua produk ada di outlet default)
+INSERT INTO outlets (name, is_default) VALUES
+('Toko Pusat', TRUE);
+
+-- Insert lokasi transit buat stock transfer yang lagi di perjalanan
+INSERT INTO outlets (name, is_transit) VALUES
+('Dalam Perjalanan', TRUE);
+
 -- Insert Categories
 INSERT INTO categories (name, description) VALUES
 ('Makanan', 'Produk makanan siap saji'),
@@ -41,13 +484,31 @@ INSERT INTO categories (name, description) VALUES
 ('Snack', 'Makanan ringan');
 
 -- Insert Products
-INSERT INTO products (name, price, stock, category_id) VALUES
-('Indomie Goreng', 3500, 100, 1),
-('Indomie Kuah', 3000, 80, 1),
-('Indomie Rendang', 4000, 50, 1),
-('Teh Botol Sosro', 5000, 60, 2),
-('Aqua 600ml', 4000, 100, 2),
-('Coca Cola', 7000, 40, 2),
-('Chitato', 12000, 30, 3),
-('Taro', 8000, 25, 3),
-('Oreo', 10000, 35, 3);
+INSERT INTO products (name, price, cost_price, stock, category_id) VALUES
+('Indomie Goreng', 3500, 2800, 100, 1),
+('Indomie Kuah', 3000, 2400, 80, 1),
+('Indomie Rendang', 4000, 3200, 50, 1),
+('Teh Botol Sosro', 5000, 3800, 60, 2),
+('Aqua 600ml', 4000, 2900, 100, 2),
+('Coca Cola', 7000, 5300, 40, 2),
+('Chitato', 12000, 9500, 30, 3),
+('Taro', 8000, 6200, 25, 3),
+('Oreo', 10000, 7800, 35, 3);
+
+-- Insert Product Units (dijual per pcs, dibeli per dus)
+INSERT INTO product_units (product_id, name, factor) VALUES
+(1, 'dus', 40),
+(5, 'dus', 24);
+
+-- Stock awal produk masuk ke outlet default
+INSERT INTO outlet_stocks (outlet_id, product_id, stock)
+SELECT o.id, p.id, p.stock
+FROM products p, outlets o
+WHERE o.is_default AND p.stock <> 0
+ON CONFLICT DO NOTHING;
+
+-- Saldo awal ledger buat produk yang stock-nya belum pernah tercatat
+INSERT INTO stock_movements (product_id, outlet_id, quantity, stock_after, outlet_stock_after, reason, reference_type, reference_id, note)
+SELECT p.id, o.id, p.stock, p.stock, p.stock, 'opening', 'product', p.id, 'saldo awal'
+FROM products p, outlets o
+WHERE o.is_default AND p.stock <> 0 AND NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.product_id = p.id);
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type CustomerHandler struct {
+	service            *services.CustomerService
+	transactionService *services.TransactionService
+	loyaltyService     *services.LoyaltyService
+}
+
+// NewCustomerHandler buat bikin instance handler baru
+func NewCustomerHandler(service *services.CustomerService, transactionService *services.TransactionService, loyaltyService *services.LoyaltyService) *CustomerHandler {
+	return &CustomerHandler{service: service, transactionService: transactionService, loyaltyService: loyaltyService}
+}
+
+// HandleCustomers buat handle GET /api/customers dan POST /api/customers
+func (h *CustomerHandler) HandleCustomers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all customers
+// @Description Mendapatkan semua customer (member). Isi phone buat cari member dari nomor HP, cukup sebagian nomornya, spasi/strip dan awalan +62 diabaikan.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param phone query string false "Cari berdasarkan nomor HP"
+// @Success 200 {array} models.Customer
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/customers [get]
+func (h *CustomerHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+
+	customers, err := h.service.GetAll(phone)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customers)
+}
+
+// Create godoc
+// @Summary Create a new customer
+// @Description Mendaftarkan customer (member) baru. member_code kosong berarti dibuatkan otomatis (M000001). Phone dan member_code tidak boleh sama dengan customer lain.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param customer body models.Customer true "Customer data"
+// @Success 201 {object} models.Customer
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/customers [post]
+func (h *CustomerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var customer models.Customer
+	err := json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customer)
+}
+
+// HandleCustomerByID buat handle GET/PUT/DELETE /api/customers/{id}, GET /api/customers/{id}/transactions
+// dan GET /api/customers/{id}/points
+func (h *CustomerHandler) HandleCustomerByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r)
+	case action == "" && r.Method == http.MethodDelete:
+		h.Delete(w, r)
+	case action == "transactions" && r.Method == http.MethodGet:
+		h.GetTransactions(w, r)
+	case action == "points" && r.Method == http.MethodGet:
+		h.GetPoints(w, r)
+	case action == "" || action == "transactions" || action == "points":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseCustomerPath buat misahin {id} dan action dari path /api/customers/{id}/{action}
+func parseCustomerPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/customers/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get customer by ID
+// @Description Mendapatkan satu customer berdasarkan ID
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Success 200 {object} models.Customer
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Customer not found"
+// @Router /api/customers/{id} [get]
+func (h *CustomerHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	customer, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// Update godoc
+// @Summary Update a customer
+// @Description Mengubah data customer. member_code kosong berarti tidak diganti.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param customer body models.Customer true "Customer data"
+// @Success 200 {object} models.Customer
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/customers/{id} [put]
+func (h *CustomerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	var customer models.Customer
+	err = json.NewDecoder(r.Body).Decode(&customer)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	customer.ID = id
+	err = h.service.Update(&customer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customer)
+}
+
+// Delete godoc
+// @Summary Delete a customer
+// @Description Menghapus customer yang belum punya transaksi
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/customers/{id} [delete]
+func (h *CustomerHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Customer deleted successfully",
+	})
+}
+
+// GetTransactions godoc
+// @Summary Riwayat belanja customer
+// @Description Mendapatkan transaksi milik satu customer, terbaru duluan, lengkap dengan detail item
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah data per halaman (default 20, maksimal 100)"
+// @Success 200 {object} models.TransactionList
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Customer not found"
+// @Router /api/customers/{id}/transactions [get]
+func (h *CustomerHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	filter := models.TransactionFilter{CustomerID: id}
+	for key, target := range map[string]*int{"page": &filter.Page, "limit": &filter.Limit} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("Invalid %s", key), http.StatusBadRequest)
+			return
+		}
+		*target = n
+	}
+
+	if _, err := h.service.GetByID(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	transactions, err := h.transactionService.GetAll(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// GetPoints godoc
+// @Summary Ledger poin customer
+// @Description Mendapatkan saldo poin member dan riwayat poinnya (earn, redeem, clawback, restore, expire), terbaru duluan.
+// @Description remaining dan expires_at menunjukkan sisa poin masuk yang belum terpakai dan kapan kadaluarsa.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah data per halaman (default 20, maksimal 100)"
+// @Success 200 {object} models.PointEntryList
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Customer not found"
+// @Router /api/customers/{id}/points [get]
+func (h *CustomerHandler) GetPoints(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseCustomerPath(r)
+	if err != nil {
+		http.Error(w, "Invalid customer ID", http.StatusBadRequest)
+		return
+	}
+
+	page, limit := 0, 0
+	for key, target := range map[string]*int{"page": &page, "limit": &limit} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("Invalid %s", key), http.StatusBadRequest)
+			return
+		}
+		*target = n
+	}
+
+	entries, err := h.loyaltyService.GetEntries(id, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type GiftCardHandler struct {
+	service *services.GiftCardService
+}
+
+// NewGiftCardHandler buat bikin instance handler baru
+func NewGiftCardHandler(service *services.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{service: service}
+}
+
+// HandleGiftCards buat handle GET /api/gift-cards dan POST /api/gift-cards
+func (h *GiftCardHandler) HandleGiftCards(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all gift cards
+// @Description Mendapatkan semua gift card/voucher beserta sisa saldonya. Code disamarkan, cuma 4 karakter terakhir yang kelihatan.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.GiftCard
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/gift-cards [get]
+func (h *GiftCardHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	giftCards, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(giftCards)
+}
+
+// Create godoc
+// @Summary Issue a new gift card
+// @Description Menerbitkan gift card/voucher baru dengan saldo initial_amount. Code dibuat acak oleh server (XXXX-XXXX-XXXX-XXXX) dan cuma ditampilkan utuh di response ini.
+// @Description expires_at opsional, kosong berarti tidak kadaluarsa.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Param gift_card body models.GiftCard true "Gift card data (initial_amount, note, expires_at)"
+// @Success 201 {object} models.GiftCard
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/gift-cards [post]
+func (h *GiftCardHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var giftCard models.GiftCard
+	err := json.NewDecoder(r.Body).Decode(&giftCard)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&giftCard)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(giftCard)
+}
+
+// GetBalance godoc
+// @Summary Cek saldo gift card
+// @Description Mengecek saldo dan masa berlaku gift card dari code-nya. Spasi, strip dan huruf kecil diabaikan.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Param code query string true "Code gift card"
+// @Success 200 {object} models.GiftCard
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Gift card not found"
+// @Router /api/gift-cards/balance [get]
+func (h *GiftCardHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	code := r.URL.Query().Get("code")
+	if strings.TrimSpace(code) == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	giftCard, err := h.service.GetByCode(code)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(giftCard)
+}
+
+// HandleGiftCardByID buat handle GET /api/gift-cards/{id} dan GET /api/gift-cards/{id}/entries
+func (h *GiftCardHandler) HandleGiftCardByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseGiftCardPath(r)
+	if err != nil {
+		http.Error(w, "Invalid gift card ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "entries" && r.Method == http.MethodGet:
+		h.GetEntries(w, r)
+	case action == "" || action == "entries":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseGiftCardPath buat misahin {id} dan action dari path /api/gift-cards/{id}/{action}
+func parseGiftCardPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/gift-cards/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get gift card by ID
+// @Description Mendapatkan satu gift card berdasarkan ID, code disamarkan
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Param id path int true "Gift card ID"
+// @Success 200 {object} models.GiftCard
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Gift card not found"
+// @Router /api/gift-cards/{id} [get]
+func (h *GiftCardHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseGiftCardPath(r)
+	if err != nil {
+		http.Error(w, "Invalid gift card ID", http.StatusBadRequest)
+		return
+	}
+
+	giftCard, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(giftCard)
+}
+
+// GetEntries godoc
+// @Summary Ledger gift card
+// @Description Mendapatkan saldo gift card dan riwayat saldonya (issue, redeem, refund), terbaru duluan.
+// @Description Baris redeem nyambung ke transaksi checkout yang memakai gift card ini, baris refund ke transaksi dan dokumen refund/void-nya.
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Param id path int true "Gift card ID"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah data per halaman (default 20, maksimal 100)"
+// @Success 200 {object} models.GiftCardEntryList
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Gift card not found"
+// @Router /api/gift-cards/{id}/entries [get]
+func (h *GiftCardHandler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseGiftCardPath(r)
+	if err != nil {
+		http.Error(w, "Invalid gift card ID", http.StatusBadRequest)
+		return
+	}
+
+	page, limit := 0, 0
+	for key, target := range map[string]*int{"page": &page, "limit": &limit} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("Invalid %s", key), http.StatusBadRequest)
+			return
+		}
+		*target = n
+	}
+
+	entries, err := h.service.GetEntries(id, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type OutletHandler struct {
+	service *services.OutletService
+}
+
+// NewOutletHandler buat bikin instance handler baru
+func NewOutletHandler(service *services.OutletService) *OutletHandler {
+	return &OutletHandler{service: service}
+}
+
+// parseOutletQuery buat ambil filter ?outlet_id=, kosong berarti 0 (semua outlet atau outlet default)
+func parseOutletQuery(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("outlet_id")
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil || id < 0 {
+		return 0, errors.New("Invalid outlet_id")
+	}
+	return id, nil
+}
+
+// HandleOutlets buat handle GET /api/outlets dan POST /api/outlets
+func (h *OutletHandler) HandleOutlets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all outlets
+// @Description Mendapatkan semua outlet (cabang toko atau gudang). Lokasi transit buat stock transfer yang lagi dikirim tidak ikut ditampilkan
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.Outlet
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/outlets [get]
+func (h *OutletHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	outlets, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlets)
+}
+
+// Create godoc
+// @Summary Create a new outlet
+// @Description Membuat outlet baru. is_default true menjadikan outlet ini default (dipakai checkout dan dokumen stock tanpa outlet_id), default yang lama dicabut.
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Param outlet body models.Outlet true "Outlet data"
+// @Success 201 {object} models.Outlet
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/outlets [post]
+func (h *OutletHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var outlet models.Outlet
+	err := json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&outlet)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// HandleOutletByID buat handle GET/PUT/DELETE /api/outlets/{id} dan GET /api/outlets/{id}/stock
+func (h *OutletHandler) HandleOutletByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseOutletPath(r)
+	if err != nil {
+		http.Error(w, "Invalid outlet ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r)
+	case action == "" && r.Method == http.MethodDelete:
+		h.Delete(w, r)
+	case action == "stock" && r.Method == http.MethodGet:
+		h.GetStock(w, r)
+	case action == "" || action == "stock":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseOutletPath buat misahin {id} dan action dari path /api/outlets/{id}/{action}
+func parseOutletPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/outlets/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get outlet by ID
+// @Description Mendapatkan satu outlet berdasarkan ID
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Success 200 {object} models.Outlet
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Outlet not found"
+// @Router /api/outlets/{id} [get]
+func (h *OutletHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseOutletPath(r)
+	if err != nil {
+		http.Error(w, "Invalid outlet ID", http.StatusBadRequest)
+		return
+	}
+
+	outlet, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// Update godoc
+// @Summary Update an outlet
+// @Description Mengubah outlet. Outlet default tidak bisa dicabut langsung, jadikan outlet lain default.
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Param outlet body models.Outlet true "Outlet data"
+// @Success 200 {object} models.Outlet
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/outlets/{id} [put]
+func (h *OutletHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseOutletPath(r)
+	if err != nil {
+		http.Error(w, "Invalid outlet ID", http.StatusBadRequest)
+		return
+	}
+
+	var outlet models.Outlet
+	err = json.NewDecoder(r.Body).Decode(&outlet)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	outlet.ID = id
+	err = h.service.Update(&outlet)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(outlet)
+}
+
+// Delete godoc
+// @Summary Delete an outlet
+// @Description Menghapus outlet yang belum punya stock, transaksi, atau dokumen stock. Outlet default tidak bisa dihapus.
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/outlets/{id} [delete]
+func (h *OutletHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseOutletPath(r)
+	if err != nil {
+		http.Error(w, "Invalid outlet ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Outlet deleted successfully",
+	})
+}
+
+// GetStock godoc
+// @Summary Stock per outlet
+// @Description Mendapatkan stock semua produk yang ada di satu outlet
+// @Tags outlets
+// @Accept json
+// @Produce json
+// @Param id path int true "Outlet ID"
+// @Success 200 {array} models.OutletStock
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Outlet not found"
+// @Router /api/outlets/{id}/stock [get]
+func (h *OutletHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseOutletPath(r)
+	if err != nil {
+		http.Error(w, "Invalid outlet ID", http.StatusBadRequest)
+		return
+	}
+
+	stocks, err := h.service.GetStock(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stocks)
+}
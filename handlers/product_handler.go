@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -11,12 +13,14 @@ import (
 )
 
 type ProductHandler struct {
-	service *services.ProductService
+	service      *services.ProductService
+	stockService *services.StockService
+	labelService *services.LabelService
 }
 
 // NewProductHandler buat bikin instance handler baru
-func NewProductHandler(service *services.ProductService) *ProductHandler {
-	return &ProductHandler{service: service}
+func NewProductHandler(service *services.ProductService, stockService *services.StockService, labelService *services.LabelService) *ProductHandler {
+	return &ProductHandler{service: service, stockService: stockService, labelService: labelService}
 }
 
 // HandleProducts buat handle GET /api/products dan POST /api/products
@@ -56,7 +60,10 @@ func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 
 // Create godoc
 // @Summary Create a new product
-// @Description Create a new product in database
+// @Description Create a new product in database. sku harus unik, barcodes berisi EAN-13 atau UPC-A (UPC-A disimpan sebagai EAN-13 dengan awalan 0).
+// @Description track_lots true buat produk yang stock-nya dilacak per batch dan tanggal kadaluarsa, stock awal masuk ke lot tanpa batch.
+// @Description base_unit itu satuan dasar stock dan harga (default pcs), units berisi satuan lain dengan factor (jumlah satuan dasar) dan price opsional, misalnya {"name": "box", "factor": 24}.
+// @Description Produk paket dibuat dengan is_bundle true dan components berisi product_id dan quantity per paket. Stock paket harus 0, stock dan HPP-nya dihitung dari komponen, min_stock dipantau di komponennya.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -83,23 +90,100 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(product)
 }
 
-// HandleProductByID buat handle GET/PUT/DELETE /api/products/{id}
+// HandleProductByID buat handle GET/PUT/DELETE /api/products/{id}, GET /api/products/{id}/stock-movements,
+// POST /api/products/{id}/stock-adjustments, GET /api/products/{id}/barcode, GET/POST /api/products/{id}/variants
+// dan GET /api/products/{id}/lots. GET /api/products/low-stock, /api/products/expiring, /api/products/by-barcode/{code}
+// dan /api/products/labels juga lewat sini karena path-nya numpang di bawah /api/products/
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetByID(w, r)
-	case http.MethodPut:
-		h.Update(w, r)
-	case http.MethodDelete:
-		h.Delete(w, r)
+	path := strings.TrimPrefix(r.URL.Path, "/api/products/")
+	if path == "low-stock" || path == "expiring" || path == "labels" || strings.HasPrefix(path, "by-barcode/") {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch path {
+		case "low-stock":
+			h.GetLowStock(w, r)
+		case "expiring":
+			h.GetExpiring(w, r)
+		case "labels":
+			h.GetLabels(w, r)
+		default:
+			h.GetByBarcode(w, r)
+		}
+		return
+	}
+
+	_, action, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			h.GetByID(w, r)
+		case http.MethodPut:
+			h.Update(w, r)
+		case http.MethodDelete:
+			h.Delete(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "stock-movements":
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.GetStockMovements(w, r)
+	case "stock-adjustments":
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.AdjustStock(w, r)
+	case "barcode":
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.GetBarcode(w, r)
+	case "variants":
+		switch r.Method {
+		case http.MethodGet:
+			h.GetVariants(w, r)
+		case http.MethodPost:
+			h.CreateVariant(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	case "lots":
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h.GetLots(w, r)
 	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		http.NotFound(w, r)
 	}
 }
 
+// parseProductPath buat misahin {id} dan action dari path /api/products/{id}/{action}
+func parseProductPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/products/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
 // GetByID godoc
 // @Summary Get product by ID
-// @Description Get a single product by ID
+// @Description Get a single product by ID. Produk induk ikut menampilkan semua variannya.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -128,7 +212,12 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 // Update godoc
 // @Summary Update a product
-// @Description Update product by ID
+// @Description Update product by ID. Field stock diabaikan, ubah stock lewat POST /api/products/{id}/stock-adjustments.
+// @Description barcodes menggantikan semua barcode lama, kalau field-nya tidak dikirim barcode lama tetap dipakai.
+// @Description components menggantikan isi paket lama kalau dikirim, is_bundle tidak bisa diubah.
+// @Description units menggantikan semua satuan lama kalau dikirim, base_unit tidak bisa diubah.
+// @Description Menyalakan track_lots memasukkan stock yang ada ke lot tanpa batch, mematikannya mengosongkan semua lot.
+// @Description Update induk ikut mengubah nama, kategori, pajak, dan harga (yang tidak price_override) semua variannya. variant_attributes tidak bisa diubah selama masih ada varian.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -165,7 +254,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Delete a product
-// @Description Delete product by ID
+// @Description Delete product by ID. Produk induk yang masih punya varian, produk yang jadi komponen paket, dan produk yang sudah punya riwayat stock (ledger stock) tidak bisa dihapus.
 // @Tags products
 // @Accept json
 // @Produce json
@@ -193,3 +282,371 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		"message": "Product deleted successfully",
 	})
 }
+
+// GetByBarcode godoc
+// @Summary Get product by barcode
+// @Description Mencari produk dari hasil scan barcode. Bisa EAN-13 atau UPC-A (12 digit), check digit divalidasi.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param code path string true "Barcode EAN-13 atau UPC-A"
+// @Success 200 {object} models.Product
+// @Failure 400 {string} string "Bad Request - barcode tidak valid"
+// @Failure 404 {string} string "Product not found"
+// @Router /api/products/by-barcode/{code} [get]
+func (h *ProductHandler) GetByBarcode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/api/products/by-barcode/")
+
+	product, err := h.service.GetByBarcode(code)
+	if errors.Is(err, services.ErrInvalidBarcode) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(product)
+}
+
+// GetVariants godoc
+// @Summary Get product variants
+// @Description Mendapatkan semua varian (misalnya ukuran atau warna) dari satu produk induk
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID induk"
+// @Success 200 {array} models.Product
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Product not found"
+// @Router /api/products/{id}/variants [get]
+func (h *ProductHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	variants, err := h.service.GetVariants(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(variants)
+}
+
+// CreateVariant godoc
+// @Summary Create a product variant
+// @Description Membuat varian baru di bawah produk induk. Induk harus punya variant_attributes (misalnya ["size", "color"]) dan stock-nya 0.
+// @Description attributes wajib berisi nilai tiap atribut induk, misalnya {"size": "L", "color": "Merah"}. Varian punya SKU, barcode, stock dan ledger sendiri.
+// @Description Nama, kategori dan pajak ikut induk. Harga ikut induk kecuali price_override true.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID induk"
+// @Param variant body models.Product true "Data varian (sku, barcodes, attributes, price, price_override, stock, min_stock, reorder_quantity)"
+// @Success 201 {object} models.Product
+// @Failure 400 {string} string "Bad Request - atribut tidak lengkap/dobel, induk masih punya stock, atau induknya varian"
+// @Router /api/products/{id}/variants [post]
+func (h *ProductHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var variant models.Product
+	err = json.NewDecoder(r.Body).Decode(&variant)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.CreateVariant(id, &variant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(variant)
+}
+
+// GetBarcode godoc
+// @Summary Gambar barcode Code128 produk
+// @Description Render barcode Code128 buat label/price tag. Isinya ID produk atau SKU, tergantung konfigurasi LABEL_BARCODE_SOURCE.
+// @Tags products
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path int true "Product ID"
+// @Param format query string false "Format gambar: png (default) atau svg"
+// @Success 200 {file} file "Gambar barcode"
+// @Failure 400 {string} string "Bad Request - format tidak dikenal"
+// @Failure 404 {string} string "Product not found"
+// @Router /api/products/{id}/barcode [get]
+func (h *ProductHandler) GetBarcode(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	var contentType string
+	switch format {
+	case "", services.BarcodeFormatPNG:
+		format, contentType = services.BarcodeFormatPNG, "image/png"
+	case services.BarcodeFormatSVG:
+		contentType = "image/svg+xml"
+	default:
+		http.Error(w, "Invalid format, use png or svg", http.StatusBadRequest)
+		return
+	}
+
+	image, err := h.labelService.RenderBarcode(id, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(image)
+}
+
+// GetLabels godoc
+// @Summary Cetak label rak (PDF)
+// @Description Membuat PDF A4 berisi label rak (nama, harga, barcode Code128), 24 label per halaman.
+// @Description Pilih produk lewat ids (dipisah koma, urutan label ikut urutan ids) atau semua produk dalam satu kategori lewat category_id.
+// @Tags products
+// @Produce application/pdf
+// @Param ids query string false "Daftar product ID dipisah koma, misalnya 1,2,3"
+// @Param category_id query int false "Cetak semua produk dalam kategori ini"
+// @Success 200 {file} file "PDF label"
+// @Failure 400 {string} string "Bad Request - ids/category_id kosong atau tidak valid"
+// @Failure 404 {string} string "Produk tidak ditemukan"
+// @Router /api/products/labels [get]
+func (h *ProductHandler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	var ids []int
+	for _, v := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid ids", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	var categoryID int
+	if v := r.URL.Query().Get("category_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid category_id", http.StatusBadRequest)
+			return
+		}
+		categoryID = n
+	}
+
+	if len(ids) == 0 && categoryID == 0 {
+		http.Error(w, "ids or category_id is required", http.StatusBadRequest)
+		return
+	}
+
+	pdf, err := h.labelService.RenderLabelSheet(ids, categoryID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="labels.pdf"`)
+	w.Write(pdf)
+}
+
+// GetLowStock godoc
+// @Summary Produk dengan stock menipis
+// @Description Mendapatkan produk yang stock-nya di bawah atau sama dengan min_stock, paling kritis duluan.
+// @Description Produk dengan min_stock 0 tidak dipantau. suggested_order_quantity itu reorder_quantity, atau kekurangan sampai min_stock kalau lebih besar.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.LowStockProduct
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/products/low-stock [get]
+func (h *ProductHandler) GetLowStock(w http.ResponseWriter, r *http.Request) {
+	products, err := h.stockService.GetLowStock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(products)
+}
+
+// GetExpiring godoc
+// @Summary Lot yang akan kadaluarsa
+// @Description Mendapatkan lot produk track_lots yang masih ada stock-nya dan kadaluarsa dalam rentang waktu tertentu, termasuk yang sudah kadaluarsa (expired true, days_left minus).
+// @Description Paling cepat kadaluarsa duluan.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param within query string false "Rentang hari ke depan, misalnya 30d atau 30 (default 30d)"
+// @Param outlet_id query int false "Hanya lot di outlet ini (default semua outlet)"
+// @Success 200 {array} models.ExpiringLot
+// @Failure 400 {string} string "Bad Request"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/products/expiring [get]
+func (h *ProductHandler) GetExpiring(w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if within := r.URL.Query().Get("within"); within != "" {
+		n, err := strconv.Atoi(strings.TrimSuffix(within, "d"))
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid within, use number of days like 30d", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+	outletID, err := parseOutletQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lots, err := h.stockService.GetExpiring(days, outletID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lots)
+}
+
+// GetLots godoc
+// @Summary Lot stock produk
+// @Description Mendapatkan lot (batch dan tanggal kadaluarsa) produk track_lots yang masih ada stock-nya, urut FEFO (paling cepat kadaluarsa duluan).
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param outlet_id query int false "Hanya lot di outlet ini (default semua outlet)"
+// @Success 200 {array} models.StockLot
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Product not found"
+// @Router /api/products/{id}/lots [get]
+func (h *ProductHandler) GetLots(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	outletID, err := parseOutletQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lots, err := h.stockService.GetLots(id, outletID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lots)
+}
+
+// GetStockMovements godoc
+// @Summary Ledger stock produk
+// @Description Mendapatkan riwayat perubahan stock produk (penjualan, refund, adjustment, penerimaan barang, stock opname), terbaru duluan.
+// @Description Response juga berisi rekonsiliasi: stock sekarang, stock hasil jumlah ledger, dan selisihnya (harusnya 0).
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah data per halaman (default 20, maksimal 100)"
+// @Success 200 {object} models.StockMovementList
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Product not found"
+// @Router /api/products/{id}/stock-movements [get]
+func (h *ProductHandler) GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var page, limit int
+	for key, target := range map[string]*int{"page": &page, "limit": &limit} {
+		value := r.URL.Query().Get(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("Invalid %s", key), http.StatusBadRequest)
+			return
+		}
+		*target = n
+	}
+
+	movements, err := h.stockService.GetMovements(id, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(movements)
+}
+
+// AdjustStock godoc
+// @Summary Stock adjustment produk
+// @Description Koreksi stock manual dengan quantity bertanda (plus nambah, minus ngurangin) dan alasan: damaged, lost, expired (harus minus), found (harus plus), atau correction (note wajib).
+// @Description Stock berubah dan tercatat di ledger dalam satu DB transaction, lengkap dengan siapa yang melakukan.
+// @Description Stock yang dikoreksi stock di outlet_id (default outlet default), outlet_stock_after itu stock di outlet itu setelah koreksi.
+// @Description Produk track_lots: lot_id buat koreksi lot tertentu (misalnya buang lot kadaluarsa), stock masuk tanpa lot_id dicatat ke batch_number/expiry_date (YYYY-MM-DD), stock keluar tanpa lot_id diambil FEFO.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param adjustment body models.StockAdjustment true "Data adjustment (quantity, reason, note, created_by)"
+// @Success 201 {object} models.StockAdjustment
+// @Failure 400 {string} string "Bad Request - alasan tidak dikenal, arah quantity salah, atau stock jadi minus"
+// @Router /api/products/{id}/stock-adjustments [post]
+func (h *ProductHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseProductPath(r)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var adjustment models.StockAdjustment
+	err = json.NewDecoder(r.Body).Decode(&adjustment)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	adjustment.ProductID = id
+	err = h.stockService.Adjust(&adjustment)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(adjustment)
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type PurchaseOrderHandler struct {
+	service *services.PurchaseOrderService
+}
+
+// NewPurchaseOrderHandler buat bikin instance handler baru
+func NewPurchaseOrderHandler(service *services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// HandlePurchaseOrders buat handle GET /api/purchase-orders dan POST /api/purchase-orders
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all purchase orders
+// @Description Mendapatkan semua purchase order beserta item-nya, terbaru duluan
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter status: draft, sent, partially_received, received, cancelled"
+// @Success 200 {array} models.PurchaseOrder
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	orders, err := h.service.GetAll(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// Create godoc
+// @Summary Create a new purchase order
+// @Description Membuat purchase order baru dengan status draft. Tiap item berisi product_id, quantity, dan unit_cost (harga beli per unit) dalam satuan dasar produk. Barang diterima ke outlet_id (kosong = outlet default).
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param purchase_order body models.PurchaseOrder true "Purchase order data"
+// @Success 201 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var po models.PurchaseOrder
+	err := json.NewDecoder(r.Body).Decode(&po)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&po)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(po)
+}
+
+// HandlePurchaseOrderByID buat handle GET/PUT /api/purchase-orders/{id} dan
+// POST /api/purchase-orders/{id}/send, /cancel, /receive
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "" && r.Method == http.MethodPut:
+		h.Update(w, r)
+	case action == "send" && r.Method == http.MethodPost:
+		h.Send(w, r)
+	case action == "cancel" && r.Method == http.MethodPost:
+		h.Cancel(w, r)
+	case action == "receive" && r.Method == http.MethodPost:
+		h.Receive(w, r)
+	case action == "" || action == "send" || action == "cancel" || action == "receive":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parsePurchaseOrderPath buat misahin {id} dan action dari path /api/purchase-orders/{id}/{action}
+func parsePurchaseOrderPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/purchase-orders/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get purchase order by ID
+// @Description Mendapatkan satu purchase order beserta item dan dokumen penerimaan barangnya
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Purchase order not found"
+// @Router /api/purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	po, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Update godoc
+// @Summary Update a purchase order
+// @Description Mengganti supplier, catatan, dan item purchase order. Hanya bisa selama status masih draft.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase Order ID"
+// @Param purchase_order body models.PurchaseOrder true "Purchase order data"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	var po models.PurchaseOrder
+	err = json.NewDecoder(r.Body).Decode(&po)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	po.ID = id
+	err = h.service.Update(&po)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Send godoc
+// @Summary Kirim purchase order
+// @Description Menandai purchase order draft sudah dikirim ke supplier (status sent)
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Bad Request - status bukan draft"
+// @Router /api/purchase-orders/{id}/send [post]
+func (h *PurchaseOrderHandler) Send(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	po, err := h.service.Send(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Cancel godoc
+// @Summary Batalkan purchase order
+// @Description Membatalkan purchase order yang masih draft atau sent (belum ada barang diterima)
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {string} string "Bad Request - barang sudah diterima atau sudah dibatalkan"
+// @Router /api/purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	po, err := h.service.Cancel(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(po)
+}
+
+// Receive godoc
+// @Summary Terima barang dari purchase order
+// @Description Mencatat penerimaan barang (boleh sebagian). Stock produk bertambah sesuai jumlah yang diterima dan harga beli per unit dicatat.
+// @Description unit_cost 0 berarti pakai harga beli di purchase order. Status jadi partially_received atau received.
+// @Description Item boleh pakai unit (misalnya box), quantity dan unit_cost dalam satuan itu lalu dikonversi ke satuan dasar.
+// @Description batch_number dan expiry_date (YYYY-MM-DD) dicatat, buat produk track_lots barangnya masuk ke lot itu.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase Order ID"
+// @Param receive body models.ReceiveRequest true "Barang yang diterima"
+// @Success 201 {object} models.GoodsReceipt
+// @Failure 400 {string} string "Bad Request - status tidak bisa menerima barang atau jumlah melebihi sisa pesanan"
+// @Router /api/purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parsePurchaseOrderPath(r)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReceiveRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.service.Receive(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(receipt)
+}
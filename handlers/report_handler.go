@@ -37,15 +37,23 @@ func (h *ReportHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
 
 // GetDailySales godoc
 // @Summary Laporan penjualan hari ini
-// @Description Mendapatkan ringkasan penjualan hari ini: total revenue, total transaksi, dan produk terlaris.
+// @Description Mendapatkan ringkasan penjualan hari ini: gross revenue, total diskon, pajak (PPN) dan service charge terkumpul, total revenue (net setelah diskon dan refund), total refund, total transaksi, produk terlaris, uang kotor yang diterima per metode pembayaran beserta refund yang balik ke gift card dan yang dibayar di luar gift card (gross_collected dikurangi total refund = total revenue), serta HPP, laba kotor, dan margin % (total, per produk, dan per kategori).
 // @Tags reports
 // @Accept json
 // @Produce json
+// @Param outlet_id query int false "Hanya transaksi di outlet ini (default semua outlet)"
 // @Success 200 {object} models.DailySalesReport "Laporan berisi total_revenue, total_transaksi, produk_terlaris"
+// @Failure 400 {string} string "Bad Request - outlet_id tidak valid"
 // @Failure 500 {string} string "Internal Server Error"
 // @Router /api/report/hari-ini [get]
 func (h *ReportHandler) GetDailySales(w http.ResponseWriter, r *http.Request) {
-	report, err := h.service.GetDailySales()
+	outletID, err := parseOutletQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetDailySales(outletID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -57,12 +65,13 @@ func (h *ReportHandler) GetDailySales(w http.ResponseWriter, r *http.Request) {
 
 // GetReportByDateRange godoc
 // @Summary Laporan penjualan berdasarkan periode
-// @Description Mendapatkan ringkasan penjualan dalam rentang tanggal tertentu. Optional challenge dari Bootcamp Session 3.
+// @Description Mendapatkan ringkasan penjualan dalam rentang tanggal tertentu. Revenue sudah dikurangi diskon dan refund di periode yang sama, lengkap dengan pajak yang terkumpul serta HPP, laba kotor, dan margin % per produk dan per kategori. Breakdown metode pembayaran itu uang kotor, refund-nya dipisah di refund_gift_card dan refund_other. Optional challenge dari Bootcamp Session 3.
 // @Tags reports
 // @Accept json
 // @Produce json
 // @Param start_date query string true "Tanggal mulai (format: YYYY-MM-DD, contoh: 2026-01-01)"
 // @Param end_date query string true "Tanggal akhir (format: YYYY-MM-DD, contoh: 2026-02-01)"
+// @Param outlet_id query int false "Hanya transaksi di outlet ini (default semua outlet)"
 // @Success 200 {object} models.DailySalesReport "Laporan berisi total_revenue, total_transaksi, produk_terlaris"
 // @Failure 400 {string} string "Bad Request - format tanggal salah"
 // @Failure 500 {string} string "Internal Server Error"
@@ -70,8 +79,13 @@ func (h *ReportHandler) GetDailySales(w http.ResponseWriter, r *http.Request) {
 func (h *ReportHandler) GetReportByDateRange(w http.ResponseWriter, r *http.Request) {
 	startDate := r.URL.Query().Get("start_date")
 	endDate := r.URL.Query().Get("end_date")
+	outletID, err := parseOutletQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	report, err := h.service.GetReportByDateRange(startDate, endDate)
+	report, err := h.service.GetReportByDateRange(startDate, endDate, outletID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
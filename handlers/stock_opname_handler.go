@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type StockOpnameHandler struct {
+	service *services.StockOpnameService
+}
+
+// NewStockOpnameHandler buat bikin instance handler baru
+func NewStockOpnameHandler(service *services.StockOpnameService) *StockOpnameHandler {
+	return &StockOpnameHandler{service: service}
+}
+
+// HandleStockOpnames buat handle GET /api/stock-opnames dan POST /api/stock-opnames
+func (h *StockOpnameHandler) HandleStockOpnames(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all stock opname sessions
+// @Description Mendapatkan semua sesi stock opname (hitung fisik stock), terbaru duluan
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.StockOpname
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/stock-opnames [get]
+func (h *StockOpnameHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	opnames, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opnames)
+}
+
+// Create godoc
+// @Summary Buka sesi stock opname
+// @Description Membuka sesi hitung fisik stock baru dengan status open di outlet_id (kosong = outlet default). Stock sistem yang dibandingkan stock outlet itu.
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Param opname body models.StockOpname true "Data sesi (name dan created_by)"
+// @Success 201 {object} models.StockOpname
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/stock-opnames [post]
+func (h *StockOpnameHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var opname models.StockOpname
+	err := json.NewDecoder(r.Body).Decode(&opname)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&opname)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(opname)
+}
+
+// HandleStockOpnameByID buat handle GET /api/stock-opnames/{id}, POST /api/stock-opnames/{id}/counts,
+// GET /api/stock-opnames/{id}/variances dan POST /api/stock-opnames/{id}/finalize
+func (h *StockOpnameHandler) HandleStockOpnameByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseStockOpnamePath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock opname ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "counts" && r.Method == http.MethodPost:
+		h.SubmitCounts(w, r)
+	case action == "variances" && r.Method == http.MethodGet:
+		h.GetVariances(w, r)
+	case action == "finalize" && r.Method == http.MethodPost:
+		h.Finalize(w, r)
+	case action == "" || action == "counts" || action == "variances" || action == "finalize":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseStockOpnamePath buat misahin {id} dan action dari path /api/stock-opnames/{id}/{action}
+func parseStockOpnamePath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/stock-opnames/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get stock opname by ID
+// @Description Mendapatkan satu sesi stock opname beserta hasil hitung per produk
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Stock opname not found"
+// @Router /api/stock-opnames/{id} [get]
+func (h *StockOpnameHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockOpnamePath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock opname ID", http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
+
+// SubmitCounts godoc
+// @Summary Submit hasil hitung fisik
+// @Description Menyimpan hitungan fisik per produk. Boleh dikirim sebagian-sebagian dari beberapa device, hitungan produk yang sudah ada diganti dengan yang terbaru.
+// @Description Stock sistem outlet dicatat saat hitungan disimpan, jadi penjualan setelah produk dihitung tidak dianggap selisih.
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Opname ID"
+// @Param counts body models.StockOpnameCountRequest true "Hitungan per produk dan counted_by"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {string} string "Bad Request - sesi sudah finalized, produk tidak ditemukan, atau quantity minus"
+// @Router /api/stock-opnames/{id}/counts [post]
+func (h *StockOpnameHandler) SubmitCounts(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockOpnamePath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock opname ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockOpnameCountRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.SubmitCounts(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
+
+// GetVariances godoc
+// @Summary Laporan selisih stock opname
+// @Description Selisih hitungan fisik vs stock sistem per produk, dinilai pakai harga produk.
+// @Description Stock sistem diambil dari saat produk dihitung. Sesi open dinilai pakai harga sekarang (preview), sesi finalized pakai harga saat finalize.
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Opname ID"
+// @Success 200 {object} models.StockOpnameVarianceReport
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Stock opname not found"
+// @Router /api/stock-opnames/{id}/variances [get]
+func (h *StockOpnameHandler) GetVariances(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockOpnamePath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock opname ID", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.GetVariances(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// Finalize godoc
+// @Summary Finalize stock opname
+// @Description Menutup sesi stock opname. Selisih hitungan vs stock sistem saat dihitung di-posting sebagai perubahan ke stock produk sekarang dan ledger (reason opname) dalam satu DB transaction.
+// @Tags stock-opnames
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Opname ID"
+// @Param finalize body models.StockOpnameFinalizeRequest true "Siapa yang finalize"
+// @Success 200 {object} models.StockOpname
+// @Failure 400 {string} string "Bad Request - sesi sudah finalized atau belum ada hitungan"
+// @Router /api/stock-opnames/{id}/finalize [post]
+func (h *StockOpnameHandler) Finalize(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockOpnamePath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock opname ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockOpnameFinalizeRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	opname, err := h.service.Finalize(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(opname)
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type StockTransferHandler struct {
+	service *services.StockTransferService
+}
+
+// NewStockTransferHandler buat bikin instance handler baru
+func NewStockTransferHandler(service *services.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{service: service}
+}
+
+// HandleStockTransfers buat handle GET /api/stock-transfers dan POST /api/stock-transfers
+func (h *StockTransferHandler) HandleStockTransfers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all stock transfers
+// @Description Mendapatkan semua transfer stock antar outlet beserta item-nya, terbaru duluan
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter status: requested, shipped, received, cancelled"
+// @Success 200 {array} models.StockTransfer
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/stock-transfers [get]
+func (h *StockTransferHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	transfers, err := h.service.GetAll(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfers)
+}
+
+// Create godoc
+// @Summary Create a stock transfer
+// @Description Membuat permintaan transfer stock dari from_outlet_id ke to_outlet_id dengan status requested.
+// @Description Tiap item berisi product_id dan quantity dalam satuan dasar produk. Stock belum berubah sampai transfer dikirim.
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param stock_transfer body models.StockTransfer true "Stock transfer data"
+// @Success 201 {object} models.StockTransfer
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/stock-transfers [post]
+func (h *StockTransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var transfer models.StockTransfer
+	err := json.NewDecoder(r.Body).Decode(&transfer)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&transfer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// HandleStockTransferByID buat handle GET /api/stock-transfers/{id} dan
+// POST /api/stock-transfers/{id}/ship, /receive, /cancel
+func (h *StockTransferHandler) HandleStockTransferByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseStockTransferPath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "ship" && r.Method == http.MethodPost:
+		h.Ship(w, r)
+	case action == "receive" && r.Method == http.MethodPost:
+		h.Receive(w, r)
+	case action == "cancel" && r.Method == http.MethodPost:
+		h.Cancel(w, r)
+	case action == "" || action == "ship" || action == "receive" || action == "cancel":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseStockTransferPath buat misahin {id} dan action dari path /api/stock-transfers/{id}/{action}
+func parseStockTransferPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/stock-transfers/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get stock transfer by ID
+// @Description Mendapatkan satu transfer stock beserta item dan lot (batch) yang dikirim
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Transfer ID"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Stock transfer not found"
+// @Router /api/stock-transfers/{id} [get]
+func (h *StockTransferHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockTransferPath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Ship godoc
+// @Summary Kirim transfer stock
+// @Description Mengirim transfer yang masih requested. Stock outlet asal langsung berkurang (ledger transfer_out) dan pindah ke lokasi transit (ledger transfer_in),
+// @Description jadi stock total produk tidak berubah dan tidak memicu alert stock menipis. Buat produk track_lots lot diambil FEFO dan dicatat di item. Status jadi shipped.
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Transfer ID"
+// @Param ship body models.StockTransferActionRequest true "Petugas yang mengirim"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {string} string "Bad Request - status bukan requested atau stock outlet asal tidak cukup"
+// @Router /api/stock-transfers/{id}/ship [post]
+func (h *StockTransferHandler) Ship(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockTransferPath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockTransferActionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Ship(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Receive godoc
+// @Summary Terima transfer stock
+// @Description Menerima transfer yang sudah shipped. Stock keluar dari lokasi transit (ledger transfer_out) dan outlet tujuan bertambah (ledger transfer_in),
+// @Description lot yang dikirim masuk ke batch dan tanggal kadaluarsa yang sama. Status jadi received.
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Transfer ID"
+// @Param receive body models.StockTransferActionRequest true "Petugas yang menerima"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {string} string "Bad Request - status bukan shipped"
+// @Router /api/stock-transfers/{id}/receive [post]
+func (h *StockTransferHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockTransferPath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.StockTransferActionRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Receive(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
+
+// Cancel godoc
+// @Summary Batalkan transfer stock
+// @Description Membatalkan transfer yang masih requested (belum dikirim, stock belum berubah)
+// @Tags stock-transfers
+// @Accept json
+// @Produce json
+// @Param id path int true "Stock Transfer ID"
+// @Success 200 {object} models.StockTransfer
+// @Failure 400 {string} string "Bad Request - transfer sudah dikirim atau dibatalkan"
+// @Router /api/stock-transfers/{id}/cancel [post]
+func (h *StockTransferHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseStockTransferPath(r)
+	if err != nil {
+		http.Error(w, "Invalid stock transfer ID", http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Cancel(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transfer)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
+)
+
+type TaxHandler struct {
+	service *services.TaxService
+}
+
+// NewTaxHandler buat bikin instance handler baru
+func NewTaxHandler(service *services.TaxService) *TaxHandler {
+	return &TaxHandler{service: service}
+}
+
+// HandleTaxRates buat handle GET /api/tax-rates dan POST /api/tax-rates
+func (h *TaxHandler) HandleTaxRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	case http.MethodPost:
+		h.Create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Get all tax rates
+// @Description Get all tax rates from database
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.TaxRate
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/tax-rates [get]
+func (h *TaxHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	taxRates, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxRates)
+}
+
+// Create godoc
+// @Summary Create a new tax rate
+// @Description Create a new tax rate. Rate dalam persen, inclusive = harga jual sudah termasuk pajak, is_default = dipakai produk yang tidak punya tarif sendiri atau dari kategori.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param taxRate body models.TaxRate true "Tax rate data"
+// @Success 201 {object} models.TaxRate
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/tax-rates [post]
+func (h *TaxHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var taxRate models.TaxRate
+	err := json.NewDecoder(r.Body).Decode(&taxRate)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Create(&taxRate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+// HandleTaxRateByID buat handle GET/PUT/DELETE /api/tax-rates/{id}
+func (h *TaxHandler) HandleTaxRateByID(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetByID(w, r)
+	case http.MethodPut:
+		h.Update(w, r)
+	case http.MethodDelete:
+		h.Delete(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetByID godoc
+// @Summary Get tax rate by ID
+// @Description Get a single tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} models.TaxRate
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Tax rate not found"
+// @Router /api/tax-rates/{id} [get]
+func (h *TaxHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/tax-rates/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid tax rate ID", http.StatusBadRequest)
+		return
+	}
+
+	taxRate, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+// Update godoc
+// @Summary Update a tax rate
+// @Description Update tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Param taxRate body models.TaxRate true "Tax rate data"
+// @Success 200 {object} models.TaxRate
+// @Failure 400 {string} string "Bad Request"
+// @Router /api/tax-rates/{id} [put]
+func (h *TaxHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/tax-rates/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid tax rate ID", http.StatusBadRequest)
+		return
+	}
+
+	var taxRate models.TaxRate
+	err = json.NewDecoder(r.Body).Decode(&taxRate)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	taxRate.ID = id
+	err = h.service.Update(&taxRate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxRate)
+}
+
+// Delete godoc
+// @Summary Delete a tax rate
+// @Description Delete tax rate by ID
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string "Bad Request"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/tax-rates/{id} [delete]
+func (h *TaxHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := strings.TrimPrefix(r.URL.Path, "/api/tax-rates/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid tax rate ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Delete(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Tax rate deleted successfully",
+	})
+}
@@ -2,18 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/services"
 )
 
 type TransactionHandler struct {
-	service *services.TransactionService
+	service        *services.TransactionService
+	receiptService *services.ReceiptService
 }
 
-func NewTransactionHandler(service *services.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
+func NewTransactionHandler(service *services.TransactionService, receiptService *services.ReceiptService) *TransactionHandler {
+	return &TransactionHandler{service: service, receiptService: receiptService}
 }
 
 // HandleCheckout buat handle POST /api/checkout
@@ -29,13 +35,26 @@ func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Reque
 // Checkout godoc
 // @Summary Proses checkout transaksi
 // @Description Membuat transaksi baru dengan multiple items. Akan mengurangi stock produk dan menghitung total amount.
+// @Description Pembayaran bisa lebih dari satu metode (cash, debit_card, qris, ewallet) dan harus menutup total amount. Kembalian hanya dari cash.
+// @Description Stock dikurangi dari outlet kasir (outlet_id, default outlet default) dan transaksi tercatat di outlet itu.
+// @Description customer_id opsional buat nyatet transaksi ke riwayat belanja member dan ngasih poin dari total yang dibayar.
+// @Description redeem_points menukar poin member jadi diskon (dihitung setelah promo, sebelum pajak), butuh customer_id.
+// @Description Item bisa ditunjuk pakai product_id atau barcode hasil scan (EAN-13/UPC-A).
+// @Description Pembayaran gift_card wajib mengisi gift_card_code, saldonya langsung dipotong sebesar amount dan tidak ada kembalian.
+// @Description unit opsional (misalnya box atau kg), quantity boleh desimal asal hasilnya bulat di satuan dasar produk.
+// @Description Produk paket dicatat satu baris di struk, stock yang dikurangi stock tiap komponennya.
+// @Description Produk track_lots diambil dari lot yang paling cepat kadaluarsa (FEFO). Lot yang sudah kadaluarsa tidak dijual kecuali allow_expired true.
+// @Description Kirim header Idempotency-Key supaya retry dengan body yang sama mengembalikan transaksi yang sama (header Idempotent-Replayed: true) tanpa mengurangi stock dua kali.
 // @Tags transactions
 // @Accept json
 // @Produce json
-// @Param checkout body models.CheckoutRequest true "Data checkout berisi items (product_id dan quantity)"
+// @Param Idempotency-Key header string false "Key unik per checkout (maksimal 255 karakter)"
+// @Param checkout body models.CheckoutRequest true "Data checkout berisi items (product_id atau barcode, dan quantity) dan payments (method dan amount)"
 // @Success 200 {object} models.Transaction "Transaksi berhasil dibuat"
-// @Failure 400 {string} string "Bad Request - items kosong atau product tidak ditemukan"
-// @Failure 500 {string} string "Internal Server Error - stock tidak cukup"
+// @Failure 400 {string} string "Bad Request - items atau payments kosong, metode pembayaran tidak dikenal, barcode tidak valid"
+// @Failure 409 {string} string "Conflict - stock yang belum kadaluarsa tidak cukup"
+// @Failure 422 {string} string "Unprocessable Entity - Idempotency-Key sudah dipakai dengan body berbeda"
+// @Failure 500 {string} string "Internal Server Error - stock tidak cukup, pembayaran kurang, atau saldo gift card tidak cukup"
 // @Router /api/checkout [post]
 func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	var req models.CheckoutRequest
@@ -50,12 +69,354 @@ func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			http.Error(w, "Quantity must be greater than 0", http.StatusBadRequest)
+			return
+		}
+		if item.ProductID == 0 && item.Barcode == "" {
+			http.Error(w, "Item must have product_id or barcode", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.CustomerID < 0 {
+		http.Error(w, "Invalid customer_id", http.StatusBadRequest)
+		return
+	}
+
+	if req.RedeemPoints < 0 {
+		http.Error(w, "redeem_points cannot be negative", http.StatusBadRequest)
+		return
+	}
+	if req.RedeemPoints > 0 && req.CustomerID == 0 {
+		http.Error(w, "redeem_points requires customer_id", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Payments) == 0 {
+		http.Error(w, "Payments cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	for _, p := range req.Payments {
+		switch p.Method {
+		case models.PaymentMethodCash, models.PaymentMethodDebitCard, models.PaymentMethodQRIS, models.PaymentMethodEWallet:
+			if p.GiftCardCode != "" {
+				http.Error(w, "gift_card_code can only be used with gift_card payments", http.StatusBadRequest)
+				return
+			}
+		case models.PaymentMethodGiftCard:
+			if strings.TrimSpace(p.GiftCardCode) == "" {
+				http.Error(w, "gift_card payment requires gift_card_code", http.StatusBadRequest)
+				return
+			}
+		default:
+			http.Error(w, fmt.Sprintf("Unknown payment method %q", p.Method), http.StatusBadRequest)
+			return
+		}
+		if p.Amount <= 0 {
+			http.Error(w, "Payment amount must be greater than 0", http.StatusBadRequest)
+			return
+		}
+	}
+
+	req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+	if len(req.IdempotencyKey) > 255 {
+		http.Error(w, "Idempotency-Key cannot be longer than 255 characters", http.StatusBadRequest)
+		return
+	}
+
+	transaction, replayed, err := h.service.Checkout(req)
+	if errors.Is(err, services.ErrIdempotencyKeyMismatch) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, services.ErrInvalidBarcode) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, services.ErrExpiredStock) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// HandleTransactions buat handle GET /api/transactions
+func (h *TransactionHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAll(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetAll godoc
+// @Summary Riwayat transaksi
+// @Description Mendapatkan daftar transaksi yang sudah terjadi, terbaru duluan, lengkap dengan detail item. Bisa difilter berdasarkan tanggal, produk, dan total amount.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param start_date query string false "Tanggal mulai (format: YYYY-MM-DD)"
+// @Param end_date query string false "Tanggal akhir (format: YYYY-MM-DD)"
+// @Param product_id query int false "Hanya transaksi yang berisi produk ini"
+// @Param outlet_id query int false "Hanya transaksi di outlet ini"
+// @Param customer_id query int false "Hanya transaksi customer ini"
+// @Param min_amount query int false "Total amount minimal"
+// @Param max_amount query int false "Total amount maksimal"
+// @Param page query int false "Halaman (default 1)"
+// @Param limit query int false "Jumlah data per halaman (default 20, maksimal 100)"
+// @Success 200 {object} models.TransactionList
+// @Failure 400 {string} string "Bad Request - format filter salah"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/transactions [get]
+func (h *TransactionHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.TransactionFilter{
+		StartDate: query.Get("start_date"),
+		EndDate:   query.Get("end_date"),
+	}
+
+	for _, date := range []string{filter.StartDate, filter.EndDate} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			http.Error(w, "Invalid date format, use YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+
+	intParams := map[string]*int{
+		"product_id":  &filter.ProductID,
+		"outlet_id":   &filter.OutletID,
+		"customer_id": &filter.CustomerID,
+		"min_amount":  &filter.MinAmount,
+		"max_amount":  &filter.MaxAmount,
+		"page":        &filter.Page,
+		"limit":       &filter.Limit,
+	}
+	for key, target := range intParams {
+		value := query.Get(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("Invalid %s", key), http.StatusBadRequest)
+			return
+		}
+		*target = n
+	}
+
+	transactions, err := h.service.GetAll(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transactions)
+}
+
+// HandleTransactionByID buat handle GET /api/transactions/{id}, GET /api/transactions/{id}/receipt,
+// POST /api/transactions/{id}/void dan POST /api/transactions/{id}/refund
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	_, action, err := parseTransactionPath(r)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.GetByID(w, r)
+	case action == "receipt" && r.Method == http.MethodGet:
+		h.Receipt(w, r)
+	case action == "void" && r.Method == http.MethodPost:
+		h.Void(w, r)
+	case action == "refund" && r.Method == http.MethodPost:
+		h.Refund(w, r)
+	case action == "" || action == "receipt" || action == "void" || action == "refund":
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// parseTransactionPath buat misahin {id} dan action dari path /api/transactions/{id}/{action}
+func parseTransactionPath(r *http.Request) (int, string, error) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/transactions/")
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, action, nil
+}
+
+// GetByID godoc
+// @Summary Get transaksi by ID
+// @Description Mendapatkan satu transaksi beserta detail item, nama produk, dan dokumen refund-nya, misalnya buat cetak ulang struk.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} models.Transaction
+// @Failure 400 {string} string "Bad Request"
+// @Failure 404 {string} string "Transaction not found"
+// @Router /api/transactions/{id} [get]
+func (h *TransactionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseTransactionPath(r)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	transaction, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(transaction)
 }
+
+// Receipt godoc
+// @Summary Cetak struk transaksi
+// @Description Render struk berisi header toko, item, total, pembayaran, dan footer dari template yang bisa dikonfigurasi.
+// @Description Format text buat tampilan plain, html buat print dari browser, escpos berupa raw bytes yang bisa langsung dikirim ke printer thermal.
+// @Tags transactions
+// @Produce plain
+// @Produce html
+// @Produce octet-stream
+// @Param id path int true "Transaction ID"
+// @Param format query string false "Format struk: text (default), html, atau escpos"
+// @Success 200 {string} string "Struk transaksi"
+// @Failure 400 {string} string "Bad Request - format tidak dikenal"
+// @Failure 404 {string} string "Transaction not found"
+// @Failure 500 {string} string "Internal Server Error"
+// @Router /api/transactions/{id}/receipt [get]
+func (h *TransactionHandler) Receipt(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseTransactionPath(r)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	var contentType string
+	switch format {
+	case "", services.ReceiptFormatText:
+		format, contentType = services.ReceiptFormatText, "text/plain; charset=utf-8"
+	case services.ReceiptFormatHTML:
+		contentType = "text/html; charset=utf-8"
+	case services.ReceiptFormatESCPOS:
+		contentType = "application/octet-stream"
+	default:
+		http.Error(w, "Invalid format, use text, html or escpos", http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := h.receiptService.Render(id, format)
+	if errors.Is(err, services.ErrTransactionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if format == services.ReceiptFormatESCPOS {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"receipt-%d.bin\"", id))
+	}
+	w.Write(receipt)
+}
+
+// Void godoc
+// @Summary Void transaksi
+// @Description Membatalkan seluruh transaksi. Semua item yang belum di-refund dikembalikan ke stock dan dicatat sebagai dokumen refund bertipe void. Poin member yang ditukar dikembalikan dan poin yang didapat ditarik lagi. Pembayaran gift card dikembalikan ke saldo gift card-nya.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param void body models.RefundRequest true "Alasan void (items diabaikan)"
+// @Success 201 {object} models.Refund
+// @Failure 400 {string} string "Bad Request - transaksi tidak ditemukan, sudah di-void, atau alasan kosong"
+// @Router /api/transactions/{id}/void [post]
+func (h *TransactionHandler) Void(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseTransactionPath(r)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.service.Void(id, req.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+// Refund godoc
+// @Summary Refund transaksi
+// @Description Refund penuh (items kosong) atau sebagian per baris transaksi. Stock produk dikembalikan dan dokumen refund dicatat dalam satu DB transaction.
+// @Description Transaksi member: poin yang ditukar dikembalikan dan poin yang didapat ditarik lagi sesuai proporsi yang di-refund (tidak sampai saldo poin minus).
+// @Description Kalau dibayar pakai gift card, nilai refund dikembalikan ke saldo gift card duluan sampai sebesar yang dibayar pakai gift card (gift_card_refunded).
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path int true "Transaction ID"
+// @Param refund body models.RefundRequest true "Alasan refund dan item yang di-refund (transaction_detail_id dan quantity)"
+// @Success 201 {object} models.Refund
+// @Failure 400 {string} string "Bad Request - quantity melebihi sisa yang bisa di-refund atau alasan kosong"
+// @Router /api/transactions/{id}/refund [post]
+func (h *TransactionHandler) Refund(w http.ResponseWriter, r *http.Request) {
+	id, _, err := parseTransactionPath(r)
+	if err != nil {
+		http.Error(w, "Invalid transaction ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RefundRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	refund, err := h.service.Refund(id, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
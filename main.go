@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -22,16 +23,31 @@ import (
 // @description API untuk sistem kasir dengan fitur manajemen produk, kategori, transaksi/checkout, dan laporan penjualan.
 // @description
 // @description ## Fitur Utama:
-// @description - **Products**: CRUD produk dengan search by name
+// @description - **Products**: CRUD produk dengan search by name, ledger pergerakan stock, stock adjustment, alert stock menipis, barcode, label rak, varian (ukuran, warna), paket/bundling, satuan (pcs, box, kg), dan lot batch/kadaluarsa (FEFO)
 // @description - **Categories**: CRUD kategori produk
+// @description - **Promotions**: CRUD promo/diskon yang otomatis dihitung saat checkout
+// @description - **Tax Rates**: Tarif pajak (PPN) inclusive/exclusive per produk, kategori, atau default
+// @description - **Suppliers**: CRUD supplier
+// @description - **Purchase Orders**: Pembelian ke supplier dan penerimaan barang yang menambah stock
+// @description - **Stock Opnames**: Sesi hitung fisik stock, laporan selisih, dan posting adjustment
+// @description - **Outlets**: Cabang toko/gudang dengan stock per outlet, checkout dan laporan bisa per outlet
+// @description - **Stock Transfers**: Pindah stock antar outlet (requested, shipped, received)
+// @description - **Customers**: Data member, cari dari nomor HP, riwayat belanja, dan poin loyalty (dapat poin, tukar poin, kadaluarsa)
+// @description - **Gift Cards**: Terbitkan gift card/voucher, cek saldo dari code, dan ledger saldonya. Dipakai bayar checkout dengan method gift_card
 // @description - **Checkout**: Proses transaksi pembelian
+// @description - **Transactions**: Riwayat transaksi, void, refund, dan cetak struk
 // @description - **Reports**: Laporan penjualan harian dan berdasarkan periode
 // @BasePath /
 
 // Config
 type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
+	Port              string  `mapstructure:"PORT"`
+	DBConn            string  `mapstructure:"DB_CONN"`
+	ServiceChargeRate float64 `mapstructure:"SERVICE_CHARGE_RATE"`
+	Receipt           services.ReceiptConfig
+	StockAlert        services.StockAlertConfig
+	Label             services.LabelConfig
+	Loyalty           services.LoyaltyConfig
 }
 
 func main() {
@@ -45,8 +61,37 @@ func main() {
 	}
 
 	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+		Port:              viper.GetString("PORT"),
+		DBConn:            viper.GetString("DB_CONN"),
+		ServiceChargeRate: viper.GetFloat64("SERVICE_CHARGE_RATE"),
+		Receipt: services.ReceiptConfig{
+			StoreName:        viper.GetString("STORE_NAME"),
+			StoreAddress:     viper.GetString("STORE_ADDRESS"),
+			StorePhone:       viper.GetString("STORE_PHONE"),
+			Footer:           viper.GetString("RECEIPT_FOOTER"),
+			Width:            viper.GetInt("RECEIPT_WIDTH"),
+			TextTemplatePath: viper.GetString("RECEIPT_TEXT_TEMPLATE"),
+			HTMLTemplatePath: viper.GetString("RECEIPT_HTML_TEMPLATE"),
+		},
+		StockAlert: services.StockAlertConfig{
+			Notifier:      viper.GetString("LOW_STOCK_NOTIFIER"),
+			CheckInterval: viper.GetInt("LOW_STOCK_CHECK_INTERVAL"),
+			WebhookURL:    viper.GetString("LOW_STOCK_WEBHOOK_URL"),
+			SMTPAddr:      viper.GetString("SMTP_ADDR"),
+			SMTPUsername:  viper.GetString("SMTP_USERNAME"),
+			SMTPPassword:  viper.GetString("SMTP_PASSWORD"),
+			SMTPFrom:      viper.GetString("SMTP_FROM"),
+			SMTPTo:        viper.GetString("SMTP_TO"),
+		},
+		Label: services.LabelConfig{
+			BarcodeSource: viper.GetString("LABEL_BARCODE_SOURCE"),
+		},
+		Loyalty: services.LoyaltyConfig{
+			EarnAmount:          viper.GetInt("LOYALTY_EARN_AMOUNT"),
+			PointValue:          viper.GetInt("LOYALTY_POINT_VALUE"),
+			ExpiryDays:          viper.GetInt("LOYALTY_EXPIRY_DAYS"),
+			ExpiryCheckInterval: viper.GetInt("LOYALTY_EXPIRY_CHECK_INTERVAL"),
+		},
 	}
 
 	// Setup database
@@ -59,16 +104,88 @@ func main() {
 	// Dependency Injection
 	productRepo := repositories.NewProductRepository(db)
 	productService := services.NewProductService(productRepo)
-	productHandler := handlers.NewProductHandler(productService)
+	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	stockAdjustmentRepo := repositories.NewStockAdjustmentRepository(db)
+
+	// Checker low stock jalan di background selama server hidup
+	stockAlertNotifier, err := services.NewStockAlertNotifier(config.StockAlert)
+	if err != nil {
+		log.Fatal("Failed to setup low stock notifier:", err)
+	}
+	stockAlertService := services.NewStockAlertService(stockMovementRepo, stockAlertNotifier, config.StockAlert.CheckInterval)
+	go stockAlertService.Run(context.Background())
+
+	stockService := services.NewStockService(stockMovementRepo, stockAdjustmentRepo, stockAlertService)
+	labelService, err := services.NewLabelService(productRepo, config.Label)
+	if err != nil {
+		log.Fatal("Failed to setup labels:", err)
+	}
+	productHandler := handlers.NewProductHandler(productService, stockService, labelService)
 
 	categoryRepo := repositories.NewCategoryRepository(db)
 	categoryService := services.NewCategoryService(categoryRepo)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
+	// Promotion
+	promotionRepo := repositories.NewPromotionRepository(db)
+	promotionService := services.NewPromotionService(promotionRepo)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+
+	// Tax
+	taxRateRepo := repositories.NewTaxRateRepository(db)
+	taxService := services.NewTaxService(taxRateRepo, config.ServiceChargeRate)
+	taxHandler := handlers.NewTaxHandler(taxService)
+
+	// Supplier dan purchase order
+	supplierRepo := repositories.NewSupplierRepository(db)
+	supplierService := services.NewSupplierService(supplierRepo)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
+	// Outlet dan transfer stock antar outlet
+	outletRepo := repositories.NewOutletRepository(db)
+	outletService := services.NewOutletService(outletRepo)
+	outletHandler := handlers.NewOutletHandler(outletService)
+
+	stockTransferRepo := repositories.NewStockTransferRepository(db)
+	stockTransferService := services.NewStockTransferService(stockTransferRepo)
+	stockTransferHandler := handlers.NewStockTransferHandler(stockTransferService)
+
+	// Stock opname
+	stockOpnameRepo := repositories.NewStockOpnameRepository(db)
+	stockOpnameService := services.NewStockOpnameService(stockOpnameRepo)
+	stockOpnameHandler := handlers.NewStockOpnameHandler(stockOpnameService)
+
+	// Poin loyalty, job poin kadaluarsa jalan di background selama server hidup
+	loyaltyRepo := repositories.NewLoyaltyRepository(db)
+	loyaltyService, err := services.NewLoyaltyService(loyaltyRepo, config.Loyalty)
+	if err != nil {
+		log.Fatal("Failed to setup loyalty points:", err)
+	}
+	go loyaltyService.Run(context.Background())
+
 	// Transaction
 	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
-	transactionHandler := handlers.NewTransactionHandler(transactionService)
+	refundRepo := repositories.NewRefundRepository(db)
+	transactionService := services.NewTransactionService(transactionRepo, refundRepo, promotionService, taxService, stockAlertService, loyaltyService)
+	receiptService, err := services.NewReceiptService(transactionService, config.Receipt)
+	if err != nil {
+		log.Fatal("Failed to load receipt template:", err)
+	}
+	transactionHandler := handlers.NewTransactionHandler(transactionService, receiptService)
+
+	// Customer
+	customerRepo := repositories.NewCustomerRepository(db)
+	customerService := services.NewCustomerService(customerRepo)
+	customerHandler := handlers.NewCustomerHandler(customerService, transactionService, loyaltyService)
+
+	// Gift Card
+	giftCardRepo := repositories.NewGiftCardRepository(db)
+	giftCardService := services.NewGiftCardService(giftCardRepo)
+	giftCardHandler := handlers.NewGiftCardHandler(giftCardService)
 
 	// Report
 	reportRepo := repositories.NewReportRepository(db)
@@ -82,8 +199,38 @@ func main() {
 	http.HandleFunc("/api/categories", categoryHandler.HandleCategories)
 	http.HandleFunc("/api/categories/", categoryHandler.HandleCategoryByID)
 
+	http.HandleFunc("/api/promotions", promotionHandler.HandlePromotions)
+	http.HandleFunc("/api/promotions/", promotionHandler.HandlePromotionByID)
+
+	http.HandleFunc("/api/tax-rates", taxHandler.HandleTaxRates)
+	http.HandleFunc("/api/tax-rates/", taxHandler.HandleTaxRateByID)
+
+	http.HandleFunc("/api/suppliers", supplierHandler.HandleSuppliers)
+	http.HandleFunc("/api/suppliers/", supplierHandler.HandleSupplierByID)
+
+	http.HandleFunc("/api/purchase-orders", purchaseOrderHandler.HandlePurchaseOrders)
+	http.HandleFunc("/api/purchase-orders/", purchaseOrderHandler.HandlePurchaseOrderByID)
+
+	http.HandleFunc("/api/outlets", outletHandler.HandleOutlets)
+	http.HandleFunc("/api/outlets/", outletHandler.HandleOutletByID)
+
+	http.HandleFunc("/api/stock-transfers", stockTransferHandler.HandleStockTransfers)
+	http.HandleFunc("/api/stock-transfers/", stockTransferHandler.HandleStockTransferByID)
+
+	http.HandleFunc("/api/stock-opnames", stockOpnameHandler.HandleStockOpnames)
+	http.HandleFunc("/api/stock-opnames/", stockOpnameHandler.HandleStockOpnameByID)
+
+	http.HandleFunc("/api/customers", customerHandler.HandleCustomers)
+	http.HandleFunc("/api/customers/", customerHandler.HandleCustomerByID)
+
+	http.HandleFunc("/api/gift-cards", giftCardHandler.HandleGiftCards)
+	http.HandleFunc("/api/gift-cards/balance", giftCardHandler.GetBalance)
+	http.HandleFunc("/api/gift-cards/", giftCardHandler.HandleGiftCardByID)
+
 	// Transaction routes
 	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
+	http.HandleFunc("/api/transactions", transactionHandler.HandleTransactions)
+	http.HandleFunc("/api/transactions/", transactionHandler.HandleTransactionByID)
 
 	// Report routes
 	http.HandleFunc("/api/report/hari-ini", reportHandler.HandleReportHariIni)
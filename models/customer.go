@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Customer itu struct buat nyimpen data member toko. MemberCode dibikin otomatis kalau kosong,
+// Phone dinormalisasi (tanpa spasi/strip, +62 jadi 0) biar gampang dicari kasir.
+// Points itu saldo poin loyalty, cuma berubah lewat checkout, refund, dan poin kadaluarsa
+type Customer struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Phone      string    `json:"phone"`
+	Email      string    `json:"email"`
+	MemberCode string    `json:"member_code"`
+	Points     int       `json:"points"`
+	CreatedAt  time.Time `json:"created_at"`
+}
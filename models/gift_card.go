@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Alasan perubahan saldo gift card
+const (
+	GiftCardReasonIssue  = "issue"
+	GiftCardReasonRedeem = "redeem"
+	GiftCardReasonRefund = "refund"
+)
+
+// GiftCard itu struct buat gift card atau voucher store credit. Code dibikin acak waktu diterbitkan
+// dan cuma ditampilkan utuh waktu diterbitkan atau dicek saldonya pakai code, selain itu disamarkan.
+// Balance cuma berkurang lewat pembayaran checkout dan balik lagi lewat void/refund transaksinya
+type GiftCard struct {
+	ID            int        `json:"id"`
+	Code          string     `json:"code"`
+	InitialAmount int        `json:"initial_amount"`
+	Balance       int        `json:"balance"`
+	Note          string     `json:"note"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// GiftCardEntry itu struct buat satu baris ledger saldo gift card. Amount bertanda (+ issue dan refund, - redeem)
+type GiftCardEntry struct {
+	ID            int       `json:"id"`
+	GiftCardID    int       `json:"gift_card_id"`
+	Amount        int       `json:"amount"`
+	BalanceAfter  int       `json:"balance_after"`
+	Reason        string    `json:"reason"`
+	TransactionID int       `json:"transaction_id,omitempty"`
+	RefundID      int       `json:"refund_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// GiftCardEntryList itu struct buat response ledger satu gift card yang dipaginasi, Balance itu saldo sekarang
+type GiftCardEntryList struct {
+	GiftCardID int             `json:"gift_card_id"`
+	Balance    int             `json:"balance"`
+	Data       []GiftCardEntry `json:"data"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	Total      int             `json:"total"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Alasan perubahan poin loyalty
+const (
+	PointReasonEarn     = "earn"
+	PointReasonRedeem   = "redeem"
+	PointReasonClawback = "clawback"
+	PointReasonRestore  = "restore"
+	PointReasonExpire   = "expire"
+)
+
+// PointEntry itu struct buat satu baris ledger poin customer. Points bertanda (+ masuk, - keluar).
+// Remaining dan ExpiresAt cuma ada di poin masuk, Remaining itu sisa poin baris ini yang belum kepake
+type PointEntry struct {
+	ID            int        `json:"id"`
+	CustomerID    int        `json:"customer_id"`
+	Points        int        `json:"points"`
+	BalanceAfter  int        `json:"balance_after"`
+	Reason        string     `json:"reason"`
+	TransactionID int        `json:"transaction_id,omitempty"`
+	RefundID      int        `json:"refund_id,omitempty"`
+	Remaining     int        `json:"remaining,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PointEntryList itu struct buat response ledger poin satu customer yang dipaginasi, Points itu saldo sekarang
+type PointEntryList struct {
+	CustomerID int          `json:"customer_id"`
+	Points     int          `json:"points"`
+	Data       []PointEntry `json:"data"`
+	Page       int          `json:"page"`
+	Limit      int          `json:"limit"`
+	Total      int          `json:"total"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// Outlet itu struct buat nyimpen data cabang toko atau gudang.
+// Outlet default dipakai kalau request nggak nyebut outlet, misalnya checkout tanpa outlet_id
+type Outlet struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// OutletStock itu struct buat stock satu produk di satu outlet
+type OutletStock struct {
+	OutletID    int    `json:"outlet_id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku,omitempty"`
+	Stock       int    `json:"stock"`
+}
@@ -3,49 +3,218 @@ package models
 import "time"
 
 // Product itu struct buat nyimpen data produk
+// TaxRateID 0 berarti ikut tarif pajak kategori (atau tarif default), TaxExempt buat produk bebas pajak.
+// CostPrice itu harga pokok rata-rata tertimbang, diupdate tiap penerimaan barang.
+// Barcodes itu daftar barcode EAN-13 produk, satu produk boleh punya beberapa.
+// MinStock itu batas stock minimum (0 = nggak dipantau), ReorderQuantity jumlah standar buat pesan ulang.
+//
+// Produk induk bisa punya varian (misalnya ukuran S/M/L atau warna). VariantAttributes itu nama atribut
+// varian di induk, misalnya ["size", "color"]. Varian itu produk juga dengan ParentID ke induknya,
+// Attributes isinya nilai tiap atribut. Nama, kategori dan pajak varian ikut induk, harganya juga
+// kecuali PriceOverride true.
+//
+// Stock, Price dan CostPrice dalam satuan dasar (BaseUnit, default pcs). Units itu satuan lain buat jual
+// atau beli, misalnya box = 24 pcs. Barang timbangan pakai satuan dasar gram dengan satuan kg = 1000 gram.
+//
+// TrackLots buat produk yang stock-nya dilacak per batch dan tanggal kadaluarsa (makanan, obat),
+// penjualannya ngambil lot yang paling cepat kadaluarsa duluan (FEFO).
+//
+// Produk paket (IsBundle) isinya Components, harganya sendiri tapi stock-nya ngikut komponen:
+// Stock paket itu jumlah paket yang masih bisa dibikin, CostPrice-nya jumlah HPP komponen
 type Product struct {
-	ID           int    `json:"id"`
-	Name         string `json:"name"`
-	Price        int    `json:"price"`
-	Stock        int    `json:"stock"`
-	CategoryID   int    `json:"category_id"`
-	CategoryName string `json:"category_name"`
+	ID              int      `json:"id"`
+	ParentID        int      `json:"parent_id,omitempty"`
+	Name            string   `json:"name"`
+	SKU             string   `json:"sku,omitempty"`
+	Barcodes        []string `json:"barcodes"`
+	Price           int      `json:"price"`
+	CostPrice       int      `json:"cost_price"`
+	Stock           int      `json:"stock"`
+	MinStock        int      `json:"min_stock"`
+	ReorderQuantity int      `json:"reorder_quantity"`
+	CategoryID      int      `json:"category_id"`
+	CategoryName    string   `json:"category_name"`
+	TaxRateID       int      `json:"tax_rate_id,omitempty"`
+	TaxExempt       bool     `json:"tax_exempt"`
+
+	BaseUnit  string        `json:"base_unit"`
+	Units     []ProductUnit `json:"units"`
+	TrackLots bool          `json:"track_lots"`
+
+	VariantAttributes []string          `json:"variant_attributes,omitempty"`
+	VariantName       string            `json:"variant_name,omitempty"`
+	Attributes        map[string]string `json:"attributes,omitempty"`
+	PriceOverride     bool              `json:"price_override,omitempty"`
+	Variants          []Product         `json:"variants,omitempty"`
+
+	IsBundle   bool              `json:"is_bundle,omitempty"`
+	Components []BundleComponent `json:"components,omitempty"`
+}
+
+// ProductUnit itu satuan jual/beli selain satuan dasar. Factor itu jumlah satuan dasar per satu satuan ini,
+// Price itu harga jual per satuan ini (0 berarti harga satuan dasar dikali Factor)
+type ProductUnit struct {
+	Name   string `json:"name"`
+	Factor int    `json:"factor"`
+	Price  int    `json:"price"`
 }
 
-// Transaction itu struct buat nyimpen data transaksi
+// BundleComponent itu struct buat satu komponen paket, Quantity itu jumlah per paket
+type BundleComponent struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+}
+
+// Transaction itu struct buat nyimpen data transaksi.
+// SubtotalAmount = GrossAmount - DiscountAmount, TotalAmount = SubtotalAmount + pajak exclusive + service charge
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID                  int                 `json:"id"`
+	OutletID            int                 `json:"outlet_id"`
+	OutletName          string              `json:"outlet_name"`
+	CustomerID          int                 `json:"customer_id,omitempty"`
+	CustomerName        string              `json:"customer_name,omitempty"`
+	GrossAmount         int                 `json:"gross_amount"`
+	DiscountAmount      int                 `json:"discount_amount"`
+	SubtotalAmount      int                 `json:"subtotal_amount"`
+	TaxAmount           int                 `json:"tax_amount"`
+	ServiceChargeAmount int                 `json:"service_charge_amount"`
+	TotalAmount         int                 `json:"total_amount"`
+	PaidAmount          int                 `json:"paid_amount"`
+	ChangeAmount        int                 `json:"change_amount"`
+	PointsRedeemed      int                 `json:"points_redeemed,omitempty"`
+	PointsDiscount      int                 `json:"points_discount,omitempty"`
+	PointsEarned        int                 `json:"points_earned,omitempty"`
+	Status              string              `json:"status"`
+	CreatedAt           time.Time           `json:"created_at"`
+	Details             []TransactionDetail `json:"details"`
+	Promotions          []AppliedPromotion  `json:"promotions"`
+	Payments            []Payment           `json:"payments"`
+	Refunds             []Refund            `json:"refunds,omitempty"`
 }
 
 // TransactionDetail itu struct buat nyimpen detail transaksi
+// Subtotal itu nilai bersih setelah DiscountAmount (diskon item + bagian diskon transaksi),
+// Total itu yang dibayar customer buat baris ini (Subtotal + pajak exclusive + bagian service charge).
+// ProductName, SKU, CategoryName, UnitPrice dan UnitCost itu snapshot waktu checkout, jadi riwayat
+// nggak berubah walaupun produknya diganti nama atau harga. ParentProductID dan ParentProductName
+// diisi kalau yang dijual itu varian, dipakai laporan buat ngegabungin varian ke produk induknya.
+// Quantity selalu dalam satuan dasar produk, Unit dan UnitQuantity itu satuan dan jumlah yang dijual
+// (misalnya 0.25 kg), UnitPrice harga per Unit
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID                  int     `json:"id"`
+	TransactionID       int     `json:"transaction_id"`
+	ProductID           int     `json:"product_id"`
+	ProductName         string  `json:"product_name,omitempty"`
+	SKU                 string  `json:"sku,omitempty"`
+	ParentProductID     int     `json:"parent_product_id,omitempty"`
+	ParentProductName   string  `json:"parent_product_name,omitempty"`
+	CategoryID          int     `json:"category_id,omitempty"`
+	CategoryName        string  `json:"category_name,omitempty"`
+	UnitPrice           int     `json:"unit_price"`
+	Quantity            int     `json:"quantity"`
+	Unit                string  `json:"unit,omitempty"`
+	UnitQuantity        float64 `json:"unit_quantity,omitempty"`
+	GrossSubtotal       int     `json:"gross_subtotal"`
+	DiscountAmount      int     `json:"discount_amount"`
+	PromotionID         int     `json:"promotion_id,omitempty"`
+	Subtotal            int     `json:"subtotal"`
+	TaxRateID           int     `json:"tax_rate_id,omitempty"`
+	TaxRate             float64 `json:"tax_rate"`
+	TaxInclusive        bool    `json:"tax_inclusive"`
+	TaxAmount           int     `json:"tax_amount"`
+	ServiceChargeAmount int     `json:"service_charge_amount"`
+	Total               int     `json:"total"`
+	UnitCost            int     `json:"unit_cost"`
+
+	// Isi paket kalau yang dijual produk paket, stock yang dikurangi stock komponennya
+	Components []TransactionDetailComponent `json:"components,omitempty"`
+
+	// Cuma dipakai waktu hitung pajak di checkout
+	TaxExempt bool `json:"-"`
 }
 
-// CheckoutItem itu struct buat item yang akan di checkout
+// TransactionDetailComponent itu snapshot satu komponen paket waktu checkout.
+// Quantity itu jumlah per paket, UnitCost itu HPP komponen per unit
+type TransactionDetailComponent struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitCost    int    `json:"unit_cost"`
+}
+
+// CheckoutItem itu struct buat item yang akan di checkout.
+// Produk boleh ditunjuk pakai product_id atau barcode hasil scan, salah satu aja.
+// Unit kosong berarti satuan dasar. Quantity boleh desimal (misalnya 0.25 kg) asal hasilnya
+// bulat di satuan dasar
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int     `json:"product_id,omitempty"`
+	Barcode   string  `json:"barcode,omitempty"`
+	Unit      string  `json:"unit,omitempty"`
+	Quantity  float64 `json:"quantity"`
 }
 
-// CheckoutRequest itu struct buat request checkout
+// CheckoutRequest itu struct buat request checkout.
+// OutletID itu outlet kasirnya, stock dikurangi dari outlet ini (0 berarti outlet default).
+// CustomerID opsional, diisi kalau pembelinya member biar masuk riwayat belanja customer itu dan dapet poin.
+// RedeemPoints itu poin member yang ditukar jadi diskon, butuh CustomerID.
+// AllowExpired buat maksa jual dari lot yang udah kadaluarsa, defaultnya diblok
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	OutletID     int               `json:"outlet_id,omitempty"`
+	CustomerID   int               `json:"customer_id,omitempty"`
+	RedeemPoints int               `json:"redeem_points,omitempty"`
+	Items        []CheckoutItem    `json:"items"`
+	Payments     []CheckoutPayment `json:"payments"`
+	AllowExpired bool              `json:"allow_expired,omitempty"`
+
+	// Diisi dari header Idempotency-Key, bukan dari body
+	IdempotencyKey string `json:"-"`
+	RequestHash    string `json:"-"`
+
+	// Diisi service dari konfigurasi loyalty, masa berlaku poin yang didapet (0 = nggak kadaluarsa)
+	PointsExpiryDays int `json:"-"`
 }
 
-// DailySalesReport itu struct buat laporan penjualan harian
+// DailySalesReport itu struct buat laporan penjualan harian. OutletID 0 berarti semua outlet
 type DailySalesReport struct {
-	TotalRevenue    int         `json:"total_revenue"`
-	TotalTransaksi  int         `json:"total_transaksi"`
-	ProdukTerlaris  *TopProduct `json:"produk_terlaris"`
+	OutletID           int                  `json:"outlet_id,omitempty"`
+	GrossRevenue       int                  `json:"gross_revenue"`
+	TotalDiscount      int                  `json:"total_discount"`
+	TotalTax           int                  `json:"total_tax"`
+	TotalServiceCharge int                  `json:"total_service_charge"`
+	TotalRevenue       int                  `json:"total_revenue"`
+	TotalRefund        int                  `json:"total_refund"`
+	TotalTransaksi     int                  `json:"total_transaksi"`
+	ProdukTerlaris     *TopProduct          `json:"produk_terlaris"`
+	PaymentMethods     []PaymentMethodTotal `json:"payment_methods"`
+
+	// Refund dipecah per tujuan uangnya: RefundGiftCard balik ke saldo gift card, RefundOther dibayar balik
+	// di luar gift card (misalnya dari laci kasir). RefundGiftCard + RefundOther = TotalRefund, jadi
+	// jumlah gross_collected di PaymentMethods - TotalRefund = TotalRevenue
+	RefundGiftCard int `json:"refund_gift_card"`
+	RefundOther    int `json:"refund_other"`
+
+	// NetSales itu penjualan bersih setelah diskon tanpa pajak dan service charge, dikurangi refund.
+	// GrossProfit = NetSales - TotalCOGS, MarginPercent = GrossProfit / NetSales * 100.
+	// ProfitPerProduct itu per varian, ProfitPerParentProduct ngegabungin semua varian ke produk induknya
+	NetSales               int          `json:"net_sales"`
+	TotalCOGS              int          `json:"total_cogs"`
+	GrossProfit            int          `json:"gross_profit"`
+	MarginPercent          float64      `json:"margin_percent"`
+	ProfitPerProduct       []ProfitLine `json:"profit_per_product"`
+	ProfitPerParentProduct []ProfitLine `json:"profit_per_parent_product"`
+	ProfitPerCategory      []ProfitLine `json:"profit_per_category"`
+}
+
+// ProfitLine itu struct buat laba kotor satu produk atau satu kategori
+type ProfitLine struct {
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	QtySold       int     `json:"qty_sold"`
+	NetSales      int     `json:"net_sales"`
+	COGS          int     `json:"cogs"`
+	GrossProfit   int     `json:"gross_profit"`
+	MarginPercent float64 `json:"margin_percent"`
 }
 
 // TopProduct itu struct buat produk terlaris
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Jenis promo
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PromotionTypeBuyXGetY   = "buy_x_get_y"
+)
+
+// Cakupan promo
+const (
+	PromotionScopeProduct     = "product"
+	PromotionScopeCategory    = "category"
+	PromotionScopeTransaction = "transaction"
+)
+
+// Promotion itu struct buat nyimpen data promo/diskon.
+// Value itu persen (1-100) buat percentage atau rupiah per item buat fixed (per transaksi kalau scope transaction).
+// Item di fixed dan buy_x_get_y itu satuan yang dijual di baris checkout (misalnya per box kalau dijual per box,
+// per kg kalau ditimbang), bukan satuan dasar produk. Buy_x_get_y cuma ngitung jumlah satuan yang bulat
+// MinSpend itu minimal belanja (total kotor transaksi) biar promo berlaku, MaxDiscount batas diskon (0 = tanpa batas)
+type Promotion struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Scope       string     `json:"scope"`
+	ProductID   int        `json:"product_id,omitempty"`
+	CategoryID  int        `json:"category_id,omitempty"`
+	Value       int        `json:"value"`
+	BuyQuantity int        `json:"buy_quantity,omitempty"`
+	GetQuantity int        `json:"get_quantity,omitempty"`
+	MinSpend    int        `json:"min_spend"`
+	MaxDiscount int        `json:"max_discount"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+	Active      bool       `json:"active"`
+}
+
+// AppliedPromotion itu struct buat promo yang kepake di satu transaksi beserta total diskonnya
+type AppliedPromotion struct {
+	PromotionID int    `json:"promotion_id"`
+	Name        string `json:"name"`
+	Scope       string `json:"scope"`
+	Amount      int    `json:"amount"`
+}
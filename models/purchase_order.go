@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// Status purchase order
+const (
+	PurchaseOrderStatusDraft             = "draft"
+	PurchaseOrderStatusSent              = "sent"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusCancelled         = "cancelled"
+)
+
+// PurchaseOrder itu struct buat pesanan pembelian barang ke supplier.
+// Barangnya diterima di OutletID, 0 waktu bikin berarti outlet default
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	OutletID     int                 `json:"outlet_id"`
+	OutletName   string              `json:"outlet_name"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Items        []PurchaseOrderItem `json:"items"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+// PurchaseOrderItem itu struct buat satu baris barang di purchase order.
+// Quantity dan UnitCost dalam satuan dasar produk.
+// UnitCost itu harga beli per unit yang disepakati, ReceivedQuantity jumlah yang udah diterima
+type PurchaseOrderItem struct {
+	ID               int    `json:"id"`
+	PurchaseOrderID  int    `json:"purchase_order_id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	UnitCost         int    `json:"unit_cost"`
+	ReceivedQuantity int    `json:"received_quantity"`
+}
+
+// GoodsReceipt itu struct buat dokumen penerimaan barang dari purchase order
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	ReceivedBy      string             `json:"received_by"`
+	Note            string             `json:"note"`
+	ReceivedAt      time.Time          `json:"received_at"`
+	Items           []GoodsReceiptItem `json:"items"`
+}
+
+// GoodsReceiptItem itu struct buat barang yang diterima beserta harga beli per unit aktualnya.
+// Quantity dan UnitCost dalam satuan dasar, Unit dan UnitQuantity itu satuan waktu diterima
+type GoodsReceiptItem struct {
+	ID                  int     `json:"id"`
+	GoodsReceiptID      int     `json:"goods_receipt_id"`
+	PurchaseOrderItemID int     `json:"purchase_order_item_id"`
+	ProductID           int     `json:"product_id"`
+	Quantity            int     `json:"quantity"`
+	UnitCost            int     `json:"unit_cost"`
+	Unit                string  `json:"unit,omitempty"`
+	UnitQuantity        float64 `json:"unit_quantity,omitempty"`
+	BatchNumber         string  `json:"batch_number,omitempty"`
+	ExpiryDate          string  `json:"expiry_date,omitempty"`
+}
+
+// ReceiveItem itu struct buat barang yang diterima di request penerimaan.
+// Unit kosong berarti satuan dasar, Quantity dan UnitCost dalam Unit (misalnya 10 box harga per box).
+// UnitCost 0 berarti pakai harga beli di purchase order. BatchNumber dan ExpiryDate (YYYY-MM-DD)
+// buat produk track_lots, barangnya masuk ke lot itu
+type ReceiveItem struct {
+	PurchaseOrderItemID int     `json:"purchase_order_item_id"`
+	Unit                string  `json:"unit,omitempty"`
+	Quantity            float64 `json:"quantity"`
+	UnitCost            int     `json:"unit_cost"`
+	BatchNumber         string  `json:"batch_number,omitempty"`
+	ExpiryDate          string  `json:"expiry_date,omitempty"`
+}
+
+// ReceiveRequest itu struct buat request penerimaan barang dari purchase order
+type ReceiveRequest struct {
+	ReceivedBy string        `json:"received_by"`
+	Note       string        `json:"note"`
+	Items      []ReceiveItem `json:"items"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import "time"
+
+// Alasan perubahan stock di ledger
+const (
+	StockReasonOpening     = "opening"
+	StockReasonSale        = "sale"
+	StockReasonRefund      = "refund"
+	StockReasonAdjustment  = "adjustment"
+	StockReasonReceiving   = "receiving"
+	StockReasonOpname      = "opname"
+	StockReasonTransferOut = "transfer_out"
+	StockReasonTransferIn  = "transfer_in"
+)
+
+// Jenis dokumen yang jadi referensi pergerakan stock
+const (
+	StockReferenceTransaction = "transaction"
+	StockReferenceRefund      = "refund"
+	StockReferenceProduct     = "product"
+	StockReferenceAdjustment  = "stock_adjustment"
+	StockReferenceOpname      = "stock_opname"
+	StockReferenceReceipt     = "goods_receipt"
+	StockReferenceTransfer    = "stock_transfer"
+)
+
+// Alasan stock adjustment. damaged, lost, expired cuma boleh ngurangin stock, found cuma boleh nambah
+const (
+	AdjustmentReasonDamaged    = "damaged"
+	AdjustmentReasonLost       = "lost"
+	AdjustmentReasonExpired    = "expired"
+	AdjustmentReasonFound      = "found"
+	AdjustmentReasonCorrection = "correction"
+)
+
+// StockMovement itu struct buat satu baris ledger stock. Quantity itu selisihnya (plus/minus),
+// StockAfter itu total stock produk semua outlet setelah perubahan ini, OutletStockAfter stock di OutletID.
+// OutletID 0 waktu mindahin stock berarti outlet default
+type StockMovement struct {
+	ID               int       `json:"id"`
+	ProductID        int       `json:"product_id"`
+	OutletID         int       `json:"outlet_id"`
+	Quantity         int       `json:"quantity"`
+	StockAfter       int       `json:"stock_after"`
+	OutletStockAfter int       `json:"outlet_stock_after"`
+	Reason           string    `json:"reason"`
+	ReferenceType    string    `json:"reference_type,omitempty"`
+	ReferenceID      int       `json:"reference_id,omitempty"`
+	Note             string    `json:"note,omitempty"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Lot yang berubah, cuma buat produk track_lots
+	Lots []StockMovementLot `json:"lots,omitempty"`
+
+	// Cuma dipakai waktu mindahin stock produk track_lots. Stock masuk ke LotID kalau diisi, kalau nggak
+	// ke lot dengan BatchNumber dan ExpiryDate yang sama (dibikin kalau belum ada). Stock keluar dari LotID
+	// kalau diisi, kalau nggak diambil FEFO (yang paling cepat kadaluarsa duluan). SkipExpired buat
+	// penjualan biar lot yang udah kadaluarsa nggak ikut diambil
+	LotID       int    `json:"-"`
+	BatchNumber string `json:"-"`
+	ExpiryDate  string `json:"-"`
+	SkipExpired bool   `json:"-"`
+}
+
+// StockMovementLot itu perubahan satu lot di satu baris ledger, Quantity bertanda
+type StockMovementLot struct {
+	LotID       int    `json:"lot_id"`
+	BatchNumber string `json:"batch_number,omitempty"`
+	ExpiryDate  string `json:"expiry_date,omitempty"`
+	Quantity    int    `json:"quantity"`
+}
+
+// StockLot itu stock satu batch produk track_lots di satu outlet. ExpiryDate format YYYY-MM-DD, kosong berarti nggak ada kadaluarsa
+type StockLot struct {
+	ID          int       `json:"id"`
+	ProductID   int       `json:"product_id"`
+	OutletID    int       `json:"outlet_id"`
+	OutletName  string    `json:"outlet_name"`
+	BatchNumber string    `json:"batch_number"`
+	ExpiryDate  string    `json:"expiry_date,omitempty"`
+	Quantity    int       `json:"quantity"`
+	Expired     bool      `json:"expired"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ExpiringLot itu struct buat laporan lot yang udah atau bakal kadaluarsa.
+// DaysLeft minus berarti udah lewat kadaluarsa sekian hari
+type ExpiringLot struct {
+	LotID       int    `json:"lot_id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"`
+	OutletID    int    `json:"outlet_id"`
+	OutletName  string `json:"outlet_name"`
+	SKU         string `json:"sku,omitempty"`
+	BatchNumber string `json:"batch_number"`
+	ExpiryDate  string `json:"expiry_date"`
+	Quantity    int    `json:"quantity"`
+	DaysLeft    int    `json:"days_left"`
+	Expired     bool   `json:"expired"`
+}
+
+// StockAdjustment itu struct buat koreksi stock manual. Quantity bertanda: plus nambah, minus ngurangin.
+// Buat produk track_lots, LotID nunjuk lot yang dikoreksi (misalnya buang lot kadaluarsa). Kalau kosong,
+// stock masuk ke lot BatchNumber/ExpiryDate dan stock keluar diambil FEFO.
+// OutletID 0 berarti outlet default. StockAfter itu total stock semua outlet, OutletStockAfter stock di outlet itu
+type StockAdjustment struct {
+	ID               int       `json:"id"`
+	ProductID        int       `json:"product_id"`
+	OutletID         int       `json:"outlet_id"`
+	Quantity         int       `json:"quantity"`
+	Reason           string    `json:"reason"`
+	Note             string    `json:"note"`
+	CreatedBy        string    `json:"created_by"`
+	LotID            int       `json:"lot_id,omitempty"`
+	BatchNumber      string    `json:"batch_number,omitempty"`
+	ExpiryDate       string    `json:"expiry_date,omitempty"`
+	StockAfter       int       `json:"stock_after"`
+	OutletStockAfter int       `json:"outlet_stock_after"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// StockMovementList itu struct buat response ledger stock satu produk yang dipaginasi.
+// LedgerStock itu jumlah semua quantity di ledger, Difference = Stock - LedgerStock (harusnya 0)
+type StockMovementList struct {
+	ProductID   int             `json:"product_id"`
+	Stock       int             `json:"stock"`
+	LedgerStock int             `json:"ledger_stock"`
+	Difference  int             `json:"difference"`
+	Data        []StockMovement `json:"data"`
+	Page        int             `json:"page"`
+	Limit       int             `json:"limit"`
+	Total       int             `json:"total"`
+}
+
+// LowStockProduct itu struct buat produk yang stock-nya udah di bawah atau sama dengan MinStock.
+// SuggestedOrderQuantity itu ReorderQuantity, atau kekurangannya dari MinStock kalau lebih besar
+type LowStockProduct struct {
+	ProductID              int    `json:"product_id"`
+	ProductName            string `json:"product_name"`
+	SKU                    string `json:"sku,omitempty"`
+	Stock                  int    `json:"stock"`
+	MinStock               int    `json:"min_stock"`
+	ReorderQuantity        int    `json:"reorder_quantity"`
+	SuggestedOrderQuantity int    `json:"suggested_order_quantity"`
+}
+
+// LowStockAlert itu struct buat notifikasi waktu satu pergerakan stock bikin produk turun
+// melewati MinStock. Stock di sini itu stock setelah pergerakan tersebut
+type LowStockAlert struct {
+	LowStockProduct
+	MovementID    int       `json:"movement_id"`
+	Reason        string    `json:"reason"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   int       `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Status sesi stock opname
+const (
+	StockOpnameStatusOpen      = "open"
+	StockOpnameStatusFinalized = "finalized"
+)
+
+// StockOpname itu struct buat satu sesi hitung fisik stock (stock opname) di satu outlet.
+// OutletID 0 waktu bikin sesi berarti outlet default
+type StockOpname struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	OutletID    int               `json:"outlet_id"`
+	OutletName  string            `json:"outlet_name"`
+	Status      string            `json:"status"`
+	CreatedBy   string            `json:"created_by"`
+	FinalizedBy string            `json:"finalized_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	FinalizedAt *time.Time        `json:"finalized_at,omitempty"`
+	Items       []StockOpnameItem `json:"items,omitempty"`
+}
+
+// StockOpnameItem itu struct buat hasil hitung satu produk di sesi stock opname.
+// SystemStock itu stock outlet waktu hitungannya disimpan, jadi penjualan setelah dihitung nggak dianggap selisih
+type StockOpnameItem struct {
+	ID              int       `json:"id"`
+	StockOpnameID   int       `json:"stock_opname_id"`
+	ProductID       int       `json:"product_id"`
+	ProductName     string    `json:"product_name"`
+	SystemStock     int       `json:"system_stock"`
+	CountedQuantity int       `json:"counted_quantity"`
+	CountedBy       string    `json:"counted_by"`
+	CountedAt       time.Time `json:"counted_at"`
+}
+
+// StockOpnameCount itu struct buat jumlah hitungan satu produk yang dikirim dari device
+type StockOpnameCount struct {
+	ProductID       int `json:"product_id"`
+	CountedQuantity int `json:"counted_quantity"`
+}
+
+// StockOpnameCountRequest itu struct buat request submit hitungan. Produk yang udah pernah dihitung
+// di sesi yang sama hitungannya diganti sama yang terbaru
+type StockOpnameCountRequest struct {
+	CountedBy string             `json:"counted_by"`
+	Items     []StockOpnameCount `json:"items"`
+}
+
+// StockOpnameFinalizeRequest itu struct buat request finalize sesi stock opname
+type StockOpnameFinalizeRequest struct {
+	FinalizedBy string `json:"finalized_by"`
+}
+
+// StockOpnameVariance itu struct buat selisih stock sistem vs hitungan fisik satu produk.
+// Variance = CountedQuantity - SystemStock, VarianceValue = Variance * UnitPrice
+type StockOpnameVariance struct {
+	ProductID       int    `json:"product_id"`
+	ProductName     string `json:"product_name"`
+	SystemStock     int    `json:"system_stock"`
+	CountedQuantity int    `json:"counted_quantity"`
+	Variance        int    `json:"variance"`
+	UnitPrice       int    `json:"unit_price"`
+	VarianceValue   int    `json:"variance_value"`
+}
+
+// StockOpnameVarianceReport itu struct buat laporan selisih satu sesi stock opname.
+// Selisihnya dari stock sistem waktu tiap produk dihitung. Sesi yang masih open dinilai pakai harga sekarang (preview),
+// yang udah finalized pakai harga waktu finalize
+type StockOpnameVarianceReport struct {
+	StockOpnameID      int                   `json:"stock_opname_id"`
+	Status             string                `json:"status"`
+	Items              []StockOpnameVariance `json:"items"`
+	TotalSurplusValue  int                   `json:"total_surplus_value"`
+	TotalShortageValue int                   `json:"total_shortage_value"`
+	NetVarianceValue   int                   `json:"net_variance_value"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Status dokumen transfer stock antar outlet
+const (
+	StockTransferStatusRequested = "requested"
+	StockTransferStatusShipped   = "shipped"
+	StockTransferStatusReceived  = "received"
+	StockTransferStatusCancelled = "cancelled"
+)
+
+// StockTransfer itu struct buat dokumen pindah stock dari satu outlet ke outlet lain.
+// Stock outlet asal berkurang waktu dikirim (shipped), stock outlet tujuan nambah waktu diterima (received).
+// Selama di perjalanan barangnya ada di lokasi transit, jadi stock total produknya tetap sama
+type StockTransfer struct {
+	ID             int                 `json:"id"`
+	FromOutletID   int                 `json:"from_outlet_id"`
+	FromOutletName string              `json:"from_outlet_name"`
+	ToOutletID     int                 `json:"to_outlet_id"`
+	ToOutletName   string              `json:"to_outlet_name"`
+	Status         string              `json:"status"`
+	Note           string              `json:"note"`
+	RequestedBy    string              `json:"requested_by"`
+	ShippedBy      string              `json:"shipped_by,omitempty"`
+	ReceivedBy     string              `json:"received_by,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	ShippedAt      *time.Time          `json:"shipped_at,omitempty"`
+	ReceivedAt     *time.Time          `json:"received_at,omitempty"`
+	Items          []StockTransferItem `json:"items"`
+}
+
+// StockTransferItem itu struct buat satu baris barang di dokumen transfer, Quantity dalam satuan dasar.
+// Lots diisi waktu dikirim buat produk track_lots, batch yang sama dipakai waktu diterima
+type StockTransferItem struct {
+	ID              int                `json:"id"`
+	StockTransferID int                `json:"stock_transfer_id"`
+	ProductID       int                `json:"product_id"`
+	ProductName     string             `json:"product_name"`
+	Quantity        int                `json:"quantity"`
+	Lots            []StockTransferLot `json:"lots,omitempty"`
+}
+
+// StockTransferLot itu batch produk track_lots yang ikut dikirim di satu baris transfer
+type StockTransferLot struct {
+	BatchNumber string `json:"batch_number"`
+	ExpiryDate  string `json:"expiry_date,omitempty"`
+	Quantity    int    `json:"quantity"`
+}
+
+// StockTransferActionRequest itu struct buat request kirim atau terima transfer, By itu nama petugasnya
+type StockTransferActionRequest struct {
+	By string `json:"by"`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Supplier itu struct buat nyimpen data pemasok barang
+type Supplier struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Address string `json:"address"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// TaxRate itu struct buat nyimpen tarif pajak (misalnya PPN 11%).
+// Inclusive berarti harga jual udah termasuk pajak, kalau nggak pajak ditambahin di atas harga
+type TaxRate struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+	IsDefault bool    `json:"is_default"`
+}
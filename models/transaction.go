@@ -0,0 +1,131 @@
+package models
+
+import "time"
+
+// TransactionFilter itu struct buat filter list riwayat transaksi
+type TransactionFilter struct {
+	StartDate  string
+	EndDate    string
+	ProductID  int
+	OutletID   int
+	CustomerID int
+	MinAmount  int
+	MaxAmount  int
+	Page       int
+	Limit      int
+}
+
+// TransactionList itu struct buat response list transaksi yang dipaginasi
+type TransactionList struct {
+	Data  []Transaction `json:"data"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+	Total int           `json:"total"`
+}
+
+// Status transaksi
+const (
+	TransactionStatusCompleted         = "completed"
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+	TransactionStatusRefunded          = "refunded"
+	TransactionStatusVoided            = "voided"
+)
+
+// Jenis dokumen refund
+const (
+	RefundTypeVoid   = "void"
+	RefundTypeRefund = "refund"
+)
+
+// Refund itu struct buat dokumen void/refund yang nyambung ke transaksi asal
+type Refund struct {
+	ID            int            `json:"id"`
+	TransactionID int            `json:"transaction_id"`
+	Type          string         `json:"type"`
+	Reason        string         `json:"reason"`
+	TotalAmount   int            `json:"total_amount"`
+	CreatedAt     time.Time      `json:"created_at"`
+	Details       []RefundDetail `json:"details"`
+
+	// Poin yang dibalikin (bagian poin yang ditukar) dan ditarik lagi (bagian poin yang didapet)
+	PointsRestored   int `json:"points_restored,omitempty"`
+	PointsClawedBack int `json:"points_clawed_back,omitempty"`
+
+	// Bagian refund yang dibalikin ke saldo gift card yang dipakai bayar transaksinya
+	GiftCardRefunded int `json:"gift_card_refunded,omitempty"`
+}
+
+// RefundDetail itu struct buat item yang di-refund
+type RefundDetail struct {
+	ID                  int    `json:"id"`
+	RefundID            int    `json:"refund_id"`
+	TransactionDetailID int    `json:"transaction_detail_id"`
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name,omitempty"`
+	Quantity            int    `json:"quantity"`
+	Amount              int    `json:"amount"`
+	TaxAmount           int    `json:"tax_amount"`
+	ServiceChargeAmount int    `json:"service_charge_amount"`
+}
+
+// RefundItem itu struct buat baris yang mau di-refund sebagian
+type RefundItem struct {
+	TransactionDetailID int `json:"transaction_detail_id"`
+	Quantity            int `json:"quantity"`
+}
+
+// RefundRequest itu struct buat request void/refund. Kalau items kosong berarti refund semua sisa item
+type RefundRequest struct {
+	Reason string       `json:"reason"`
+	Items  []RefundItem `json:"items"`
+
+	// Diisi service dari konfigurasi loyalty, masa berlaku poin yang dibalikin (0 = nggak kadaluarsa)
+	PointsExpiryDays int `json:"-"`
+}
+
+// Metode pembayaran yang diterima kasir
+const (
+	PaymentMethodCash      = "cash"
+	PaymentMethodDebitCard = "debit_card"
+	PaymentMethodQRIS      = "qris"
+	PaymentMethodEWallet   = "ewallet"
+	PaymentMethodGiftCard  = "gift_card"
+)
+
+// CheckoutPayment itu struct buat pembayaran yang diserahkan customer waktu checkout.
+// GiftCardCode wajib diisi kalau method-nya gift_card, saldonya langsung dipotong sebesar Amount
+type CheckoutPayment struct {
+	Method       string `json:"method"`
+	Amount       int    `json:"amount"`
+	GiftCardCode string `json:"gift_card_code,omitempty"`
+}
+
+// Payment itu struct buat pembayaran yang tersimpan per transaksi. Amount itu bagian yang dipakai
+// buat bayar total, Tendered itu uang yang diserahkan (beda cuma di cash kalau ada kembalian).
+// GiftCardCode disamarkan, cuma 4 karakter terakhir yang kelihatan
+type Payment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method"`
+	Amount        int    `json:"amount"`
+	Tendered      int    `json:"tendered"`
+	GiftCardID    int    `json:"gift_card_id,omitempty"`
+	GiftCardCode  string `json:"gift_card_code,omitempty"`
+}
+
+// PaymentMethodTotal itu struct buat uang yang diterima per metode pembayaran di laporan. GrossCollected itu
+// kotor, belum dikurangi refund, termasuk transaksi yang belakangan di-void (void-nya masuk ke refund).
+// TotalTransaksi cuma ngitung transaksi yang nggak di-void
+type PaymentMethodTotal struct {
+	Method         string `json:"method"`
+	GrossCollected int    `json:"gross_collected"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
+
+// IdempotencyKey itu struct buat nyimpen key checkout beserta hash request dan response aslinya
+type IdempotencyKey struct {
+	Key           string
+	RequestHash   string
+	TransactionID int
+	ResponseBody  []byte
+}
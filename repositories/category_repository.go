@@ -18,7 +18,7 @@ func NewCategoryRepository(db *sql.DB) *CategoryRepository {
 
 // GetAll buat ambil semua categories dari database
 func (r *CategoryRepository) GetAll() ([]models.Category, error) {
-	query := "SELECT id, name, description FROM categories"
+	query := "SELECT id, name, COALESCE(description, ''), COALESCE(tax_rate_id, 0) FROM categories"
 	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -28,7 +28,7 @@ func (r *CategoryRepository) GetAll() ([]models.Category, error) {
 	categories := make([]models.Category, 0)
 	for rows.Next() {
 		var c models.Category
-		err := rows.Scan(&c.ID, &c.Name, &c.Description)
+		err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID)
 		if err != nil {
 			return nil, err
 		}
@@ -40,10 +40,10 @@ func (r *CategoryRepository) GetAll() ([]models.Category, error) {
 
 // GetByID buat ambil category berdasarkan ID
 func (r *CategoryRepository) GetByID(id int) (*models.Category, error) {
-	query := "SELECT id, name, description FROM categories WHERE id = $1"
+	query := "SELECT id, name, COALESCE(description, ''), COALESCE(tax_rate_id, 0) FROM categories WHERE id = $1"
 
 	var c models.Category
-	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description)
+	err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Name, &c.Description, &c.TaxRateID)
 	if err == sql.ErrNoRows {
 		return nil, errors.New("category not found")
 	}
@@ -56,15 +56,15 @@ func (r *CategoryRepository) GetByID(id int) (*models.Category, error) {
 
 // Create buat bikin category baru
 func (r *CategoryRepository) Create(category *models.Category) error {
-	query := "INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id"
-	err := r.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
+	query := "INSERT INTO categories (name, description, tax_rate_id) VALUES ($1, $2, $3) RETURNING id"
+	err := r.db.QueryRow(query, category.Name, category.Description, nullInt(category.TaxRateID)).Scan(&category.ID)
 	return err
 }
 
 // Update buat update category yang udah ada
 func (r *CategoryRepository) Update(category *models.Category) error {
-	query := "UPDATE categories SET name = $1, description = $2 WHERE id = $3"
-	result, err := r.db.Exec(query, category.Name, category.Description, category.ID)
+	query := "UPDATE categories SET name = $1, description = $2, tax_rate_id = $3 WHERE id = $4"
+	result, err := r.db.Exec(query, category.Name, category.Description, nullInt(category.TaxRateID), category.ID)
 	if err != nil {
 		return err
 	}
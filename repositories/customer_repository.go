@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type CustomerRepository struct {
+	db *sql.DB
+}
+
+// NewCustomerRepository buat bikin instance repository baru
+func NewCustomerRepository(db *sql.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+const customerColumns = `id, name, COALESCE(phone, ''), COALESCE(email, ''), member_code, points, created_at`
+
+// GetAll buat ambil semua customers, kalau phone diisi cuma customer yang nomornya mengandung phone
+func (r *CustomerRepository) GetAll(phone string) ([]models.Customer, error) {
+	query := "SELECT " + customerColumns + " FROM customers"
+	args := []interface{}{}
+	if phone != "" {
+		query += " WHERE phone LIKE '%' || $1 || '%'"
+		args = append(args, phone)
+	}
+	query += " ORDER BY id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := make([]models.Customer, 0)
+	for rows.Next() {
+		var c models.Customer
+		err := rows.Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.MemberCode, &c.Points, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, c)
+	}
+
+	return customers, rows.Err()
+}
+
+// GetByID buat ambil customer berdasarkan ID
+func (r *CustomerRepository) GetByID(id int) (*models.Customer, error) {
+	var c models.Customer
+	err := r.db.QueryRow("SELECT "+customerColumns+" FROM customers WHERE id = $1", id).
+		Scan(&c.ID, &c.Name, &c.Phone, &c.Email, &c.MemberCode, &c.Points, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("customer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Create buat bikin customer baru dengan saldo poin 0. Kalau member code kosong, dibikinin dari ID-nya (M000001)
+func (r *CustomerRepository) Create(customer *models.Customer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// ID diambil duluan biar member code default bisa dibikin dari ID-nya
+	if err := tx.QueryRow("SELECT nextval('customers_id_seq')").Scan(&customer.ID); err != nil {
+		return err
+	}
+	if customer.MemberCode == "" {
+		customer.MemberCode = fmt.Sprintf("M%06d", customer.ID)
+	}
+	if err := checkCustomerCodes(tx, customer); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO customers (id, name, phone, email, member_code)
+		 VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5) RETURNING points, created_at`,
+		customer.ID, customer.Name, customer.Phone, customer.Email, customer.MemberCode,
+	).Scan(&customer.Points, &customer.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update buat update customer yang udah ada. Member code kosong berarti nggak diganti, saldo poin nggak ikut diubah
+func (r *CustomerRepository) Update(customer *models.Customer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkCustomerCodes(tx, customer); err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		`UPDATE customers SET name = $1, phone = NULLIF($2, ''), email = NULLIF($3, ''), member_code = COALESCE(NULLIF($4, ''), member_code)
+		 WHERE id = $5 RETURNING member_code, points, created_at`,
+		customer.Name, customer.Phone, customer.Email, customer.MemberCode, customer.ID,
+	).Scan(&customer.MemberCode, &customer.Points, &customer.CreatedAt)
+	if err == sql.ErrNoRows {
+		return errors.New("customer not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkCustomerCodes buat mastiin phone dan member code belum dipakai customer lain, biar errornya
+// jelas dan nggak cuma unique violation dari database
+func checkCustomerCodes(tx *sql.Tx, customer *models.Customer) error {
+	if customer.Phone != "" {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM customers WHERE phone = $1 AND id <> $2)", customer.Phone, customer.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("phone " + customer.Phone + " is already used by another customer")
+		}
+	}
+	if customer.MemberCode != "" {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM customers WHERE member_code = $1 AND id <> $2)", customer.MemberCode, customer.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("member_code " + customer.MemberCode + " is already used by another customer")
+		}
+	}
+	return nil
+}
+
+// Delete buat hapus customer. Customer yang udah punya transaksi nggak bisa dihapus biar riwayatnya tetap utuh
+func (r *CustomerRepository) Delete(id int) error {
+	var used bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM transactions WHERE customer_id = $1)", id).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.New("customer already has transactions")
+	}
+
+	result, err := r.db.Exec("DELETE FROM customers WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("customer not found")
+	}
+
+	return nil
+}
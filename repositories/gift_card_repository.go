@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+// ErrInsufficientGiftCardBalance dikembalikan kalau perubahan saldo bikin saldo gift card jadi minus
+var ErrInsufficientGiftCardBalance = errors.New("insufficient gift card balance")
+
+type GiftCardRepository struct {
+	db *sql.DB
+}
+
+// NewGiftCardRepository buat bikin instance repository baru
+func NewGiftCardRepository(db *sql.DB) *GiftCardRepository {
+	return &GiftCardRepository{db: db}
+}
+
+const giftCardColumns = `id, code, initial_amount, balance, COALESCE(note, ''), expires_at, created_at`
+
+func scanGiftCard(row interface{ Scan(...interface{}) error }) (*models.GiftCard, error) {
+	var g models.GiftCard
+	err := row.Scan(&g.ID, &g.Code, &g.InitialAmount, &g.Balance, &g.Note, &g.ExpiresAt, &g.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// GetAll buat ambil semua gift card, code-nya disamarkan
+func (r *GiftCardRepository) GetAll() ([]models.GiftCard, error) {
+	rows, err := r.db.Query("SELECT " + giftCardColumns + " FROM gift_cards ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	giftCards := make([]models.GiftCard, 0)
+	for rows.Next() {
+		g, err := scanGiftCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		g.Code = maskGiftCardCode(g.Code)
+		giftCards = append(giftCards, *g)
+	}
+
+	return giftCards, rows.Err()
+}
+
+// GetByID buat ambil gift card berdasarkan ID, code-nya disamarkan
+func (r *GiftCardRepository) GetByID(id int) (*models.GiftCard, error) {
+	g, err := scanGiftCard(r.db.QueryRow("SELECT "+giftCardColumns+" FROM gift_cards WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("gift card not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	g.Code = maskGiftCardCode(g.Code)
+	return g, nil
+}
+
+// GetByCode buat cek saldo gift card dari code-nya
+func (r *GiftCardRepository) GetByCode(code string) (*models.GiftCard, error) {
+	g, err := scanGiftCard(r.db.QueryRow("SELECT "+giftCardColumns+" FROM gift_cards WHERE code = $1", code))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("gift card not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// Create buat nerbitin gift card baru. Saldo awalnya masuk lewat ledger biar saldo dan ledger selalu cocok
+func (r *GiftCardRepository) Create(giftCard *models.GiftCard) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
+		`INSERT INTO gift_cards (code, initial_amount, balance, note, expires_at)
+		 VALUES ($1, $2, 0, NULLIF($3, ''), $4) RETURNING id, created_at`,
+		giftCard.Code, giftCard.InitialAmount, giftCard.Note, giftCard.ExpiresAt,
+	).Scan(&giftCard.ID, &giftCard.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.GiftCardEntry{GiftCardID: giftCard.ID, Amount: giftCard.InitialAmount, Reason: models.GiftCardReasonIssue}
+	if err := moveGiftCardBalance(tx, entry); err != nil {
+		return err
+	}
+	giftCard.Balance = entry.BalanceAfter
+
+	return tx.Commit()
+}
+
+// GetEntries buat ambil ledger satu gift card, terbaru duluan, sekalian saldonya
+func (r *GiftCardRepository) GetEntries(giftCardID, page, limit int) (balance int, entries []models.GiftCardEntry, total int, err error) {
+	err = r.db.QueryRow(
+		"SELECT balance, (SELECT COUNT(*) FROM gift_card_entries WHERE gift_card_id = $1) FROM gift_cards WHERE id = $1",
+		giftCardID,
+	).Scan(&balance, &total)
+	if err == sql.ErrNoRows {
+		return 0, nil, 0, errors.New("gift card not found")
+	}
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, gift_card_id, amount, balance_after, reason, COALESCE(transaction_id, 0), COALESCE(refund_id, 0), created_at
+		 FROM gift_card_entries
+		 WHERE gift_card_id = $1
+		 ORDER BY id DESC
+		 LIMIT $2 OFFSET $3`,
+		giftCardID, limit, (page-1)*limit,
+	)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	defer rows.Close()
+
+	entries = make([]models.GiftCardEntry, 0)
+	for rows.Next() {
+		var e models.GiftCardEntry
+		err := rows.Scan(&e.ID, &e.GiftCardID, &e.Amount, &e.BalanceAfter, &e.Reason, &e.TransactionID, &e.RefundID, &e.CreatedAt)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+
+	return balance, entries, total, rows.Err()
+}
+
+// lockGiftCards buat lock gift card yang dipakai bayar checkout, urut ID biar dua checkout yang pakai
+// gift card sama nggak deadlock. Dipanggil setelah lock produk dan customer. Sekalian ngecek code-nya ada,
+// belum kadaluarsa, dan saldonya cukup buat semua pembayaran yang pakai code itu. Balikin ID per code
+func lockGiftCards(tx *sql.Tx, payments []models.CheckoutPayment) (map[string]int, error) {
+	requested := make(map[string]int)
+	codes := make([]string, 0)
+	for _, p := range payments {
+		if p.Method != models.PaymentMethodGiftCard {
+			continue
+		}
+		if _, ok := requested[p.GiftCardCode]; !ok {
+			codes = append(codes, p.GiftCardCode)
+		}
+		requested[p.GiftCardCode] += p.Amount
+	}
+	ids := make(map[string]int)
+	if len(codes) == 0 {
+		return ids, nil
+	}
+
+	rows, err := tx.Query(
+		`SELECT id, code, balance, COALESCE(expires_at <= CURRENT_TIMESTAMP, FALSE)
+		 FROM gift_cards WHERE code = ANY($1) ORDER BY id FOR UPDATE`,
+		pq.Array(codes),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, balance int
+		var code string
+		var expired bool
+		if err := rows.Scan(&id, &code, &balance, &expired); err != nil {
+			return nil, err
+		}
+		if expired {
+			return nil, fmt.Errorf("gift card %s has expired", maskGiftCardCode(code))
+		}
+		if balance < requested[code] {
+			return nil, fmt.Errorf("insufficient gift card balance for %s (available: %d, requested: %d)",
+				maskGiftCardCode(code), balance, requested[code])
+		}
+		ids[code] = id
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		if _, ok := ids[code]; !ok {
+			return nil, fmt.Errorf("gift card %s not found", maskGiftCardCode(code))
+		}
+	}
+	return ids, nil
+}
+
+// moveGiftCardBalance itu satu-satunya jalan buat ngubah saldo gift card: saldo di gift_cards diubah dan
+// dicatat di ledger dalam DB transaction yang sama. Update-nya bersyarat jadi saldo nggak mungkin minus
+// walaupun dua checkout motong barengan. Balikin ErrInsufficientGiftCardBalance kalau saldonya nggak cukup
+func moveGiftCardBalance(tx *sql.Tx, e *models.GiftCardEntry) error {
+	err := tx.QueryRow(
+		"UPDATE gift_cards SET balance = balance + $1 WHERE id = $2 AND balance + $1 >= 0 RETURNING balance",
+		e.Amount, e.GiftCardID,
+	).Scan(&e.BalanceAfter)
+	if err == sql.ErrNoRows {
+		return ErrInsufficientGiftCardBalance
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.QueryRow(
+		`INSERT INTO gift_card_entries (gift_card_id, amount, balance_after, reason, transaction_id, refund_id)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		e.GiftCardID, e.Amount, e.BalanceAfter, e.Reason, nullInt(e.TransactionID), nullInt(e.RefundID),
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+// maskGiftCardCode buat nyamarin code gift card, cuma 4 karakter terakhir yang kelihatan
+func maskGiftCardCode(code string) string {
+	if len(code) <= 4 {
+		return strings.Repeat("*", len(code))
+	}
+	masked := []byte(code[:len(code)-4])
+	for i, c := range masked {
+		if c != '-' {
+			masked[i] = '*'
+		}
+	}
+	return string(masked) + code[len(code)-4:]
+}
@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+// ErrInsufficientPoints dikembalikan kalau perubahan poin bikin saldo poin customer jadi minus
+var ErrInsufficientPoints = errors.New("insufficient points")
+
+type LoyaltyRepository struct {
+	db *sql.DB
+}
+
+// NewLoyaltyRepository buat bikin instance repository baru
+func NewLoyaltyRepository(db *sql.DB) *LoyaltyRepository {
+	return &LoyaltyRepository{db: db}
+}
+
+// GetEntries buat ambil ledger poin satu customer, terbaru duluan, sekalian saldo poinnya
+func (r *LoyaltyRepository) GetEntries(customerID, page, limit int) (points int, entries []models.PointEntry, total int, err error) {
+	err = r.db.QueryRow(
+		"SELECT points, (SELECT COUNT(*) FROM loyalty_point_entries WHERE customer_id = $1) FROM customers WHERE id = $1",
+		customerID,
+	).Scan(&points, &total)
+	if err == sql.ErrNoRows {
+		return 0, nil, 0, errors.New("customer not found")
+	}
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, customer_id, points, balance_after, reason, COALESCE(transaction_id, 0), COALESCE(refund_id, 0),
+		 remaining, expires_at, created_at
+		 FROM loyalty_point_entries
+		 WHERE customer_id = $1
+		 ORDER BY id DESC
+		 LIMIT $2 OFFSET $3`,
+		customerID, limit, (page-1)*limit,
+	)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	defer rows.Close()
+
+	entries = make([]models.PointEntry, 0)
+	for rows.Next() {
+		var e models.PointEntry
+		err := rows.Scan(&e.ID, &e.CustomerID, &e.Points, &e.BalanceAfter, &e.Reason, &e.TransactionID, &e.RefundID,
+			&e.Remaining, &e.ExpiresAt, &e.CreatedAt)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+
+	return points, entries, total, rows.Err()
+}
+
+// ExpirePoints buat ngangusin semua poin yang udah lewat masa berlakunya, satu DB transaction per customer.
+// Balikin jumlah poin yang hangus dan jumlah customer-nya
+func (r *LoyaltyRepository) ExpirePoints() (expired int, customers int, err error) {
+	rows, err := r.db.Query(
+		`SELECT DISTINCT customer_id FROM loyalty_point_entries
+		 WHERE remaining > 0 AND expires_at <= CURRENT_TIMESTAMP
+		 ORDER BY customer_id`,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	customerIDs := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		customerIDs = append(customerIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for _, id := range customerIDs {
+		points, err := r.expireCustomer(id)
+		if err != nil {
+			return expired, customers, err
+		}
+		if points > 0 {
+			expired += points
+			customers++
+		}
+	}
+
+	return expired, customers, nil
+}
+
+func (r *LoyaltyRepository) expireCustomer(customerID int) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCustomer(tx, customerID); err != nil {
+		return 0, err
+	}
+	points, err := expireCustomerPoints(tx, customerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return points, tx.Commit()
+}
+
+// lockCustomer buat lock row customer, semua perubahan poin satu customer antri di sini.
+// Dipanggil setelah lock produk (kalau ada) biar urutannya sama kayak checkout dan refund
+func lockCustomer(tx *sql.Tx, customerID int) error {
+	var id int
+	err := tx.QueryRow("SELECT id FROM customers WHERE id = $1 FOR UPDATE", customerID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("customer not found")
+	}
+	return err
+}
+
+// expireCustomerPoints buat ngangusin poin customer yang udah kadaluarsa, row customer harus udah di-lock.
+// Poin yang kadaluarsa pasti yang paling cepat kadaluarsa, jadi movePoints ngambilnya dari baris-baris itu
+func expireCustomerPoints(tx *sql.Tx, customerID int) (int, error) {
+	var points int
+	err := tx.QueryRow(
+		`SELECT COALESCE(SUM(remaining), 0) FROM loyalty_point_entries
+		 WHERE customer_id = $1 AND remaining > 0 AND expires_at <= CURRENT_TIMESTAMP`,
+		customerID,
+	).Scan(&points)
+	if err != nil || points == 0 {
+		return 0, err
+	}
+
+	err = movePoints(tx, &models.PointEntry{CustomerID: customerID, Points: -points, Reason: models.PointReasonExpire}, 0)
+	return points, err
+}
+
+// movePoints itu satu-satunya jalan buat ngubah saldo poin customer: saldo di customers diubah dan
+// dicatat di ledger dalam DB transaction yang sama. Poin masuk berlaku expiryDays hari (0 = nggak kadaluarsa),
+// poin keluar ngurangin sisa poin masuk yang paling cepat kadaluarsa duluan.
+// Balikin ErrInsufficientPoints kalau saldo jadi minus
+func movePoints(tx *sql.Tx, e *models.PointEntry, expiryDays int) error {
+	err := tx.QueryRow(
+		"UPDATE customers SET points = points + $1 WHERE id = $2 AND points + $1 >= 0 RETURNING points",
+		e.Points, e.CustomerID,
+	).Scan(&e.BalanceAfter)
+	if err == sql.ErrNoRows {
+		return ErrInsufficientPoints
+	}
+	if err != nil {
+		return err
+	}
+
+	e.Remaining = max(e.Points, 0)
+	err = tx.QueryRow(
+		`INSERT INTO loyalty_point_entries (customer_id, points, balance_after, reason, transaction_id, refund_id, remaining, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $7::int > 0 AND $8::int > 0 THEN CURRENT_TIMESTAMP + $8::int * INTERVAL '1 day' END)
+		 RETURNING id, expires_at, created_at`,
+		e.CustomerID, e.Points, e.BalanceAfter, e.Reason, nullInt(e.TransactionID), nullInt(e.RefundID), e.Remaining, expiryDays,
+	).Scan(&e.ID, &e.ExpiresAt, &e.CreatedAt)
+	if err != nil || e.Points >= 0 {
+		return err
+	}
+
+	rows, err := tx.Query(
+		`SELECT id, remaining FROM loyalty_point_entries
+		 WHERE customer_id = $1 AND remaining > 0
+		 ORDER BY expires_at NULLS LAST, id FOR UPDATE`,
+		e.CustomerID,
+	)
+	if err != nil {
+		return err
+	}
+	type take struct{ id, points int }
+	takes := make([]take, 0)
+	need := -e.Points
+	for rows.Next() && need > 0 {
+		var id, remaining int
+		if err := rows.Scan(&id, &remaining); err != nil {
+			rows.Close()
+			return err
+		}
+		t := take{id, min(remaining, need)}
+		takes = append(takes, t)
+		need -= t.points
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if need > 0 {
+		return ErrInsufficientPoints
+	}
+
+	for _, t := range takes {
+		if _, err := tx.Exec("UPDATE loyalty_point_entries SET remaining = remaining - $1 WHERE id = $2", t.points, t.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type OutletRepository struct {
+	db *sql.DB
+}
+
+// NewOutletRepository buat bikin instance repository baru
+func NewOutletRepository(db *sql.DB) *OutletRepository {
+	return &OutletRepository{db: db}
+}
+
+// GetAll buat ambil semua outlets dari database, lokasi transit nggak ikut
+func (r *OutletRepository) GetAll() ([]models.Outlet, error) {
+	rows, err := r.db.Query("SELECT id, name, address, is_default FROM outlets WHERE NOT is_transit ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	outlets := make([]models.Outlet, 0)
+	for rows.Next() {
+		var o models.Outlet
+		err := rows.Scan(&o.ID, &o.Name, &o.Address, &o.IsDefault)
+		if err != nil {
+			return nil, err
+		}
+		outlets = append(outlets, o)
+	}
+
+	return outlets, rows.Err()
+}
+
+// GetByID buat ambil outlet berdasarkan ID, lokasi transit dianggap nggak ada
+func (r *OutletRepository) GetByID(id int) (*models.Outlet, error) {
+	var o models.Outlet
+	err := r.db.QueryRow("SELECT id, name, address, is_default FROM outlets WHERE id = $1 AND NOT is_transit", id).Scan(&o.ID, &o.Name, &o.Address, &o.IsDefault)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("outlet not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// Create buat bikin outlet baru. Kalau is_default, outlet default yang lama dicabut dulu
+func (r *OutletRepository) Create(outlet *models.Outlet) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if outlet.IsDefault {
+		if _, err := tx.Exec("UPDATE outlets SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow(
+		"INSERT INTO outlets (name, address, is_default) VALUES ($1, $2, $3) RETURNING id",
+		outlet.Name, outlet.Address, outlet.IsDefault,
+	).Scan(&outlet.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update buat update outlet yang udah ada. Kalau is_default, outlet default yang lama dicabut dulu.
+// Outlet default nggak bisa dicabut langsung, harus pilih outlet lain jadi default
+func (r *OutletRepository) Update(outlet *models.Outlet) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var isDefault bool
+	err = tx.QueryRow("SELECT is_default FROM outlets WHERE id = $1 AND NOT is_transit FOR UPDATE", outlet.ID).Scan(&isDefault)
+	if err == sql.ErrNoRows {
+		return errors.New("outlet not found")
+	}
+	if err != nil {
+		return err
+	}
+	if isDefault && !outlet.IsDefault {
+		return errors.New("cannot unset the default outlet, set another outlet as default instead")
+	}
+
+	if outlet.IsDefault {
+		if _, err := tx.Exec("UPDATE outlets SET is_default = FALSE WHERE is_default AND id <> $1", outlet.ID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE outlets SET name = $1, address = $2, is_default = $3 WHERE id = $4",
+		outlet.Name, outlet.Address, outlet.IsDefault, outlet.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete buat hapus outlet. Outlet default dan outlet yang udah punya stock atau riwayat nggak bisa dihapus
+func (r *OutletRepository) Delete(id int) error {
+	var isDefault, used bool
+	err := r.db.QueryRow(
+		`SELECT is_default,
+		 EXISTS (SELECT 1 FROM stock_movements WHERE outlet_id = $1) OR EXISTS (SELECT 1 FROM transactions WHERE outlet_id = $1)
+		 OR EXISTS (SELECT 1 FROM stock_transfers WHERE from_outlet_id = $1 OR to_outlet_id = $1)
+		 OR EXISTS (SELECT 1 FROM purchase_orders WHERE outlet_id = $1) OR EXISTS (SELECT 1 FROM stock_opnames WHERE outlet_id = $1)
+		 FROM outlets WHERE id = $1 AND NOT is_transit`,
+		id,
+	).Scan(&isDefault, &used)
+	if err == sql.ErrNoRows {
+		return errors.New("outlet not found")
+	}
+	if err != nil {
+		return err
+	}
+	if isDefault {
+		return errors.New("cannot delete the default outlet")
+	}
+	if used {
+		return errors.New("outlet already has stock or transaction history")
+	}
+
+	_, err = r.db.Exec("DELETE FROM outlets WHERE id = $1", id)
+	return err
+}
+
+// GetStock buat ambil stock semua produk yang ada di satu outlet
+func (r *OutletRepository) GetStock(id int) ([]models.OutletStock, error) {
+	if _, err := r.GetByID(id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT os.outlet_id, os.product_id, p.name, COALESCE(p.sku, ''), os.stock
+		 FROM outlet_stocks os
+		 JOIN products p ON p.id = os.product_id
+		 WHERE os.outlet_id = $1 AND os.stock > 0
+		 ORDER BY p.id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stocks := make([]models.OutletStock, 0)
+	for rows.Next() {
+		var s models.OutletStock
+		if err := rows.Scan(&s.OutletID, &s.ProductID, &s.ProductName, &s.SKU, &s.Stock); err != nil {
+			return nil, err
+		}
+		stocks = append(stocks, s)
+	}
+
+	return stocks, rows.Err()
+}
+
+// resolveOutlet buat mastiin outlet ada dan balikin ID dan namanya. ID 0 berarti outlet default.
+// Lokasi transit dianggap nggak ada, jadi outlet dari request nggak bisa nunjuk ke situ.
+// Bisa dipanggil pakai *sql.DB atau *sql.Tx
+func resolveOutlet(q interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}, id int) (int, string, error) {
+	var name string
+	var err error
+	if id == 0 {
+		err = q.QueryRow("SELECT id, name FROM outlets WHERE is_default").Scan(&id, &name)
+		if err == sql.ErrNoRows {
+			return 0, "", errors.New("no default outlet configured")
+		}
+	} else {
+		err = q.QueryRow("SELECT name FROM outlets WHERE id = $1 AND NOT is_transit", id).Scan(&name)
+		if err == sql.ErrNoRows {
+			return 0, "", errors.New("outlet not found")
+		}
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return id, name, nil
+}
+
+// transitOutlet buat ambil ID lokasi transit, tempat stock transfer yang udah dikirim tapi belum diterima
+func transitOutlet(tx *sql.Tx) (int, error) {
+	var id int
+	err := tx.QueryRow("SELECT id FROM outlets WHERE is_transit").Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, errors.New("no transit outlet configured")
+	}
+	return id, err
+}
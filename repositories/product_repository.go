@@ -2,11 +2,24 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
 )
 
+// ErrProductHasVariants dikembalikan kalau stock produk induk mau diubah, stock-nya dicatat per varian
+var ErrProductHasVariants = errors.New("product has variants, stock is tracked per variant")
+
+// ErrBundleStock dikembalikan kalau stock produk paket mau diubah langsung, stock-nya ngikut komponen
+var ErrBundleStock = errors.New("product is a bundle, stock is tracked on its components")
+
 type ProductRepository struct {
 	db *sql.DB
 }
@@ -16,17 +29,69 @@ func NewProductRepository(db *sql.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
+// Stock paket itu jumlah paket yang masih bisa dibikin dari stock komponennya, HPP-nya jumlah HPP komponen
+const productColumns = `p.id, COALESCE(p.parent_id, 0), p.name, COALESCE(p.sku, ''), p.price,
+	CASE WHEN p.is_bundle THEN COALESCE((SELECT SUM(cp.cost_price * bc.quantity) FROM bundle_components bc
+		JOIN products cp ON cp.id = bc.component_id WHERE bc.bundle_id = p.id), 0) ELSE p.cost_price END,
+	CASE WHEN p.is_bundle THEN COALESCE((SELECT MIN(cp.stock / bc.quantity) FROM bundle_components bc
+		JOIN products cp ON cp.id = bc.component_id WHERE bc.bundle_id = p.id), 0) ELSE p.stock END,
+	p.min_stock, p.reorder_quantity,
+	COALESCE(p.category_id, 0), COALESCE(c.name, '') as category_name, COALESCE(p.tax_rate_id, 0), p.tax_exempt,
+	COALESCE(p.variant_attributes, '{}'), COALESCE(p.variant_name, ''), COALESCE(p.attributes::text, ''), p.price_override,
+	p.is_bundle, p.base_unit, p.track_lots`
+
+// scanProduct buat scan satu row products sesuai urutan productColumns
+func scanProduct(row interface{ Scan(...interface{}) error }) (*models.Product, error) {
+	var p models.Product
+	var variantAttributes pq.StringArray
+	var attributes string
+	err := row.Scan(&p.ID, &p.ParentID, &p.Name, &p.SKU, &p.Price, &p.CostPrice, &p.Stock, &p.MinStock, &p.ReorderQuantity,
+		&p.CategoryID, &p.CategoryName, &p.TaxRateID, &p.TaxExempt,
+		&variantAttributes, &p.VariantName, &attributes, &p.PriceOverride, &p.IsBundle, &p.BaseUnit, &p.TrackLots)
+	if err != nil {
+		return nil, err
+	}
+	if len(variantAttributes) > 0 {
+		p.VariantAttributes = variantAttributes
+	}
+	if attributes != "" {
+		if err := json.Unmarshal([]byte(attributes), &p.Attributes); err != nil {
+			return nil, err
+		}
+	}
+	return &p, nil
+}
+
 // GetAll buat ambil semua products dari database
 func (r *ProductRepository) GetAll(nameFilter string) ([]models.Product, error) {
-	query := `SELECT p.id, p.name, p.price, p.stock, p.category_id, COALESCE(c.name, '') as category_name
-			  FROM products p
-			  LEFT JOIN categories c ON p.category_id = c.id`
-
-	args := []interface{}{}
 	if nameFilter != "" {
-		query += " WHERE p.name ILIKE $1"
-		args = append(args, "%"+nameFilter+"%")
+		return r.list("WHERE p.name ILIKE $1", "%"+nameFilter+"%")
+	}
+	return r.list("")
+}
+
+// GetByIDs buat ambil beberapa product sekaligus, urut ID
+func (r *ProductRepository) GetByIDs(ids []int) ([]models.Product, error) {
+	productIDs := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		productIDs = append(productIDs, int64(id))
 	}
+	return r.list("WHERE p.id = ANY($1) ORDER BY p.id", pq.Array(productIDs))
+}
+
+// GetByCategoryID buat ambil semua product dalam satu kategori, urut nama
+func (r *ProductRepository) GetByCategoryID(categoryID int) ([]models.Product, error) {
+	return r.list("WHERE p.category_id = $1 ORDER BY p.name, p.id", categoryID)
+}
+
+// GetVariants buat ambil semua varian satu produk induk, urut ID
+func (r *ProductRepository) GetVariants(parentID int) ([]models.Product, error) {
+	return r.list("WHERE p.parent_id = $1 ORDER BY p.id", parentID)
+}
+
+// list buat ambil products dengan filter/urutan tambahan, barcode-nya sekalian diisi
+func (r *ProductRepository) list(where string, args ...interface{}) ([]models.Product, error) {
+	query := "SELECT " + productColumns + " FROM products p LEFT JOIN categories c ON p.category_id = c.id " + where
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
@@ -36,12 +101,24 @@ func (r *ProductRepository) GetAll(nameFilter string) ([]models.Product, error)
 
 	products := make([]models.Product, 0)
 	for rows.Next() {
-		var p models.Product
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName)
+		p, err := scanProduct(rows)
 		if err != nil {
 			return nil, err
 		}
-		products = append(products, p)
+		products = append(products, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadBarcodes(products); err != nil {
+		return nil, err
+	}
+	if err := r.loadBundleComponents(products); err != nil {
+		return nil, err
+	}
+	if err := r.loadUnits(products); err != nil {
+		return nil, err
 	}
 
 	return products, nil
@@ -49,52 +126,831 @@ func (r *ProductRepository) GetAll(nameFilter string) ([]models.Product, error)
 
 // GetByID buat ambil product berdasarkan ID
 func (r *ProductRepository) GetByID(id int) (*models.Product, error) {
-	query := `SELECT p.id, p.name, p.price, p.stock, p.category_id, COALESCE(c.name, '') as category_name
-			  FROM products p
-			  LEFT JOIN categories c ON p.category_id = c.id
-			  WHERE p.id = $1`
+	products, err := r.list("WHERE p.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, errors.New("product not found")
+	}
+	return &products[0], nil
+}
 
-	var p models.Product
-	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.CategoryName)
+// GetByBarcode buat ambil product berdasarkan barcode EAN-13 yang udah dinormalisasi
+func (r *ProductRepository) GetByBarcode(barcode string) (*models.Product, error) {
+	var id int
+	err := r.db.QueryRow("SELECT product_id FROM product_barcodes WHERE barcode = $1", barcode).Scan(&id)
 	if err == sql.ErrNoRows {
 		return nil, errors.New("product not found")
 	}
 	if err != nil {
 		return nil, err
 	}
+	return r.GetByID(id)
+}
 
-	return &p, nil
+// loadBarcodes buat ngisi Barcodes semua product sekaligus dalam satu query
+func (r *ProductRepository) loadBarcodes(products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(products))
+	index := make(map[int]int, len(products))
+	for i := range products {
+		products[i].Barcodes = make([]string, 0)
+		ids = append(ids, int64(products[i].ID))
+		index[products[i].ID] = i
+	}
+
+	rows, err := r.db.Query("SELECT product_id, barcode FROM product_barcodes WHERE product_id = ANY($1) ORDER BY id", pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var barcode string
+		if err := rows.Scan(&productID, &barcode); err != nil {
+			return err
+		}
+		p := &products[index[productID]]
+		p.Barcodes = append(p.Barcodes, barcode)
+	}
+
+	return rows.Err()
+}
+
+// loadUnits buat ngisi Units semua product sekaligus dalam satu query
+func (r *ProductRepository) loadUnits(products []models.Product) error {
+	ids := make([]int64, 0, len(products))
+	for i := range products {
+		ids = append(ids, int64(products[i].ID))
+	}
+
+	units, err := getProductUnits(r.db, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Units = units[products[i].ID]
+		if products[i].Units == nil {
+			products[i].Units = make([]models.ProductUnit, 0)
+		}
+	}
+	return nil
+}
+
+// getProductUnits buat ambil satuan tambahan beberapa produk, per product_id. Bisa dipanggil
+// pakai *sql.DB atau *sql.Tx
+func getProductUnits(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, productIDs []int64) (map[int][]models.ProductUnit, error) {
+	units := make(map[int][]models.ProductUnit)
+	if len(productIDs) == 0 {
+		return units, nil
+	}
+
+	rows, err := q.Query(
+		"SELECT product_id, name, factor, price FROM product_units WHERE product_id = ANY($1) ORDER BY factor, id",
+		pq.Array(productIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int
+		var u models.ProductUnit
+		if err := rows.Scan(&productID, &u.Name, &u.Factor, &u.Price); err != nil {
+			return nil, err
+		}
+		units[productID] = append(units[productID], u)
+	}
+	return units, rows.Err()
+}
+
+// findUnit buat nyari satuan jual/beli produk. Unit kosong atau sama dengan satuan dasar berarti
+// satuan dasar dengan factor 1 dan harga basePrice
+func findUnit(baseUnit string, basePrice int, units []models.ProductUnit, name string) (models.ProductUnit, error) {
+	if name == "" || name == baseUnit {
+		return models.ProductUnit{Name: baseUnit, Factor: 1, Price: basePrice}, nil
+	}
+	for _, u := range units {
+		if u.Name == name {
+			if u.Price == 0 {
+				u.Price = basePrice * u.Factor
+			}
+			return u, nil
+		}
+	}
+	return models.ProductUnit{}, fmt.Errorf("unit %s is not defined for this product", name)
+}
+
+// toBaseQuantity buat ngubah jumlah di satuan tertentu ke satuan dasar. Hasilnya harus bulat,
+// jadi 0.25 kg (1 kg = 1000 gram) boleh tapi 0.5 pcs nggak
+func toBaseQuantity(quantity float64, unit models.ProductUnit, baseUnit string) (int, error) {
+	base := quantity * float64(unit.Factor)
+	rounded := math.Round(base)
+	if math.Abs(base-rounded) > 1e-6 || rounded <= 0 {
+		return 0, fmt.Errorf("quantity %s %s is not a whole number of %s",
+			strconv.FormatFloat(quantity, 'f', -1, 64), unit.Name, baseUnit)
+	}
+	return int(rounded), nil
+}
+
+// insertProductUnits buat nyimpen satuan tambahan produk, nama satuannya nggak boleh sama dengan satuan dasar
+func insertProductUnits(tx *sql.Tx, product *models.Product) error {
+	for _, u := range product.Units {
+		if u.Name == product.BaseUnit {
+			return errors.New("unit " + u.Name + " is already the base unit")
+		}
+	}
+	for _, u := range product.Units {
+		_, err := tx.Exec(
+			"INSERT INTO product_units (product_id, name, factor, price) VALUES ($1, $2, $3, $4)",
+			product.ID, u.Name, u.Factor, u.Price,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Create buat bikin product baru
+// loadBundleComponents buat ngisi Components semua produk paket sekaligus dalam satu query
+func (r *ProductRepository) loadBundleComponents(products []models.Product) error {
+	ids := make([]int64, 0)
+	index := make(map[int]int)
+	for i := range products {
+		if products[i].IsBundle {
+			products[i].Components = make([]models.BundleComponent, 0)
+			ids = append(ids, int64(products[i].ID))
+			index[products[i].ID] = i
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT bc.bundle_id, bc.component_id, cp.name, bc.quantity
+		 FROM bundle_components bc
+		 JOIN products cp ON cp.id = bc.component_id
+		 WHERE bc.bundle_id = ANY($1)
+		 ORDER BY bc.id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bundleID int
+		var c models.BundleComponent
+		if err := rows.Scan(&bundleID, &c.ProductID, &c.ProductName, &c.Quantity); err != nil {
+			return err
+		}
+		p := &products[index[bundleID]]
+		p.Components = append(p.Components, c)
+	}
+
+	return rows.Err()
+}
+
+// Create buat bikin product baru, stock awalnya dicatat sebagai saldo awal di ledger
 func (r *ProductRepository) Create(product *models.Product) error {
-	query := "INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	err := r.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertProduct(tx, product); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Update buat update product yang udah ada
-func (r *ProductRepository) Update(product *models.Product) error {
-	query := "UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4 WHERE id = $5"
-	result, err := r.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+// CreateVariant buat bikin varian baru di bawah produk induk. Nama, kategori, pajak dan harga
+// (kalau nggak PriceOverride) diambil dari induknya. Induk yang masih punya stock nggak bisa
+// dikasih varian, stock-nya harus dipindah ke varian dulu lewat stock adjustment
+func (r *ProductRepository) CreateVariant(parentID int, variant *models.Product) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rows, err := result.RowsAffected()
+	parent, err := lockVariantParent(tx, parentID, "FOR UPDATE")
 	if err != nil {
 		return err
 	}
+	if parent.Stock != 0 {
+		return errors.New("parent product still has stock, move it to the variants with a stock adjustment first")
+	}
 
-	if rows == 0 {
+	var inBundle bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM bundle_components WHERE component_id = $1)", parentID).Scan(&inBundle)
+	if err != nil {
+		return err
+	}
+	if inBundle {
+		return errors.New("parent product is used as a bundle component, use a variant in the bundle instead")
+	}
+
+	variant.ID = 0
+	variant.ParentID = parentID
+	variant.IsBundle, variant.Components = false, nil
+	if err := applyVariantParent(tx, variant, parent); err != nil {
+		return err
+	}
+
+	if err := insertProduct(tx, variant); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertProduct buat insert product (atau varian) beserta barcode dan saldo awal ledger-nya
+func insertProduct(tx *sql.Tx, product *models.Product) error {
+	if err := checkProductCodes(tx, product); err != nil {
+		return err
+	}
+
+	attributes, err := marshalAttributes(product.Attributes)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO products (parent_id, name, sku, price, cost_price, stock, min_stock, reorder_quantity, category_id, tax_rate_id, tax_exempt,
+			  variant_attributes, variant_name, attributes, price_override, is_bundle, base_unit, track_lots)
+			  VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), $14, $15, $16, $17, $18) RETURNING id`
+	err = tx.QueryRow(query, nullInt(product.ParentID), product.Name, product.SKU, product.Price, product.CostPrice, product.Stock,
+		product.MinStock, product.ReorderQuantity, nullInt(product.CategoryID), nullInt(product.TaxRateID), product.TaxExempt,
+		nullStringArray(product.VariantAttributes), product.VariantName, attributes, product.PriceOverride, product.IsBundle,
+		product.BaseUnit, product.TrackLots).Scan(&product.ID)
+	if err != nil {
+		return err
+	}
+
+	if product.Units == nil {
+		product.Units = make([]models.ProductUnit, 0)
+	}
+	if err := insertProductUnits(tx, product); err != nil {
+		return err
+	}
+
+	if product.IsBundle {
+		if err := replaceBundleComponents(tx, product); err != nil {
+			return err
+		}
+		if err := loadBundleTotals(tx, product); err != nil {
+			return err
+		}
+	}
+
+	if product.Barcodes == nil {
+		product.Barcodes = make([]string, 0)
+	}
+	if err := insertProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+		return err
+	}
+
+	if product.Stock != 0 {
+		// Saldo awal masuk ke outlet default, pindah ke outlet lain lewat transfer stock
+		outletID, _, err := resolveOutlet(tx, 0)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("INSERT INTO outlet_stocks (outlet_id, product_id, stock) VALUES ($1, $2, $3)", outletID, product.ID, product.Stock)
+		if err != nil {
+			return err
+		}
+
+		opening := &models.StockMovement{
+			ProductID:        product.ID,
+			OutletID:         outletID,
+			Quantity:         product.Stock,
+			StockAfter:       product.Stock,
+			OutletStockAfter: product.Stock,
+			Reason:           models.StockReasonOpening,
+			ReferenceType:    models.StockReferenceProduct,
+			ReferenceID:      product.ID,
+			Note:             "saldo awal",
+		}
+		if err := insertStockMovement(tx, opening); err != nil {
+			return err
+		}
+		// Saldo awal produk track_lots masuk ke lot tanpa batch dan tanpa kadaluarsa
+		if product.TrackLots {
+			if err := moveLots(tx, opening); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Update buat update product yang udah ada. Stock nggak ikut diubah, perubahan stock harus lewat
+// stock adjustment biar tercatat di ledger. Cost price juga nggak ikut, dihitung dari penerimaan barang.
+// product.Stock dan product.CostPrice diisi nilai yang sekarang.
+// Barcodes dan VariantAttributes nil berarti nilai lama dibiarin.
+// Kalau yang di-update itu varian, nama/kategori/pajak/harga dihitung ulang dari induknya.
+// Kalau yang di-update itu induk, perubahannya ikut diterusin ke semua varian
+func (r *ProductRepository) Update(product *models.Product) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID int
+	var variantAttributes pq.StringArray
+	var hasVariants, trackedLots bool
+	err = tx.QueryRow(
+		`SELECT COALESCE(parent_id, 0), COALESCE(variant_attributes, '{}'),
+		 EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id), is_bundle, track_lots
+		 FROM products p WHERE id = $1 FOR UPDATE`,
+		product.ID,
+	).Scan(&parentID, &variantAttributes, &hasVariants, &product.IsBundle, &trackedLots)
+	if err == sql.ErrNoRows {
 		return errors.New("product not found")
 	}
+	if err != nil {
+		return err
+	}
 
+	product.ParentID = parentID
+	if parentID != 0 {
+		parent, err := lockVariantParent(tx, parentID, "FOR SHARE")
+		if err != nil {
+			return err
+		}
+		if err := applyVariantParent(tx, product, parent); err != nil {
+			return err
+		}
+	} else {
+		product.VariantName, product.Attributes, product.PriceOverride = "", nil, false
+		if product.VariantAttributes == nil {
+			product.VariantAttributes = variantAttributes
+		}
+		if hasVariants && !sameStrings(product.VariantAttributes, variantAttributes) {
+			return errors.New("variant_attributes cannot be changed while the product has variants")
+		}
+		if product.IsBundle && len(product.VariantAttributes) > 0 {
+			return errors.New("bundle cannot have variants")
+		}
+	}
+
+	if err := checkProductCodes(tx, product); err != nil {
+		return err
+	}
+
+	// Jenis produk (paket atau bukan) nggak bisa diubah, isi paket nil berarti isi lama dibiarin
+	if !product.IsBundle {
+		product.Components = nil
+	} else {
+		// Alert stock menipis dipantau di komponennya, bukan di paket
+		product.MinStock, product.ReorderQuantity = 0, 0
+	}
+	if product.IsBundle && product.Components != nil {
+		if err := replaceBundleComponents(tx, product); err != nil {
+			return err
+		}
+	}
+
+	attributes, err := marshalAttributes(product.Attributes)
+	if err != nil {
+		return err
+	}
+
+	if product.IsBundle && product.TrackLots {
+		return errors.New("bundle cannot track lots, track them on its components")
+	}
+
+	// Satuan dasar nggak bisa diganti karena stock, harga dan ledger-nya udah dalam satuan itu
+	query := `UPDATE products SET name = $1, sku = NULLIF($2, ''), price = $3, min_stock = $4, reorder_quantity = $5,
+			  category_id = $6, tax_rate_id = $7, tax_exempt = $8,
+			  variant_attributes = $9, variant_name = NULLIF($10, ''), attributes = $11, price_override = $12, track_lots = $13
+			  WHERE id = $14 RETURNING stock, cost_price, base_unit`
+	err = tx.QueryRow(query, product.Name, product.SKU, product.Price, product.MinStock, product.ReorderQuantity,
+		nullInt(product.CategoryID), nullInt(product.TaxRateID), product.TaxExempt,
+		nullStringArray(product.VariantAttributes), product.VariantName, attributes, product.PriceOverride, product.TrackLots,
+		product.ID).Scan(&product.Stock, &product.CostPrice, &product.BaseUnit)
+	if err != nil {
+		return err
+	}
+
+	if err := switchLotTracking(tx, product, trackedLots); err != nil {
+		return err
+	}
+
+	if product.Units != nil {
+		if _, err := tx.Exec("DELETE FROM product_units WHERE product_id = $1", product.ID); err != nil {
+			return err
+		}
+		if err := insertProductUnits(tx, product); err != nil {
+			return err
+		}
+	} else {
+		units, err := getProductUnits(tx, []int64{int64(product.ID)})
+		if err != nil {
+			return err
+		}
+		product.Units = units[product.ID]
+		if product.Units == nil {
+			product.Units = make([]models.ProductUnit, 0)
+		}
+	}
+
+	if product.Barcodes != nil {
+		if _, err := tx.Exec("DELETE FROM product_barcodes WHERE product_id = $1", product.ID); err != nil {
+			return err
+		}
+		if err := insertProductBarcodes(tx, product.ID, product.Barcodes); err != nil {
+			return err
+		}
+	} else {
+		product.Barcodes = make([]string, 0)
+		rows, err := tx.Query("SELECT barcode FROM product_barcodes WHERE product_id = $1 ORDER BY id", product.ID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var barcode string
+			if err := rows.Scan(&barcode); err != nil {
+				return err
+			}
+			product.Barcodes = append(product.Barcodes, barcode)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+
+	if product.IsBundle {
+		if err := loadBundleTotals(tx, product); err != nil {
+			return err
+		}
+	}
+
+	if hasVariants {
+		_, err = tx.Exec(
+			`UPDATE products SET name = $1::text || ' - ' || variant_name, category_id = $2, tax_rate_id = $3, tax_exempt = $4,
+			 price = CASE WHEN price_override THEN price ELSE $5 END
+			 WHERE parent_id = $6`,
+			product.Name, nullInt(product.CategoryID), nullInt(product.TaxRateID), product.TaxExempt, product.Price, product.ID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// switchLotTracking buat nyalain/matiin track_lots. Waktu dinyalain, stock yang udah ada di tiap outlet dimasukin
+// ke lot tanpa batch dan tanpa kadaluarsa. Waktu dimatiin, semua lot dikosongin (riwayatnya tetap ada di ledger lot)
+func switchLotTracking(tx *sql.Tx, product *models.Product, trackedLots bool) error {
+	if product.TrackLots == trackedLots {
+		return nil
+	}
+	if !product.TrackLots {
+		_, err := tx.Exec("UPDATE stock_lots SET quantity = 0 WHERE product_id = $1", product.ID)
+		return err
+	}
+
+	rows, err := tx.Query("SELECT outlet_id, stock FROM outlet_stocks WHERE product_id = $1 AND stock > 0 ORDER BY outlet_id", product.ID)
+	if err != nil {
+		return err
+	}
+	stocks := make(map[int]int)
+	outletIDs := make([]int, 0)
+	for rows.Next() {
+		var outletID, stock int
+		if err := rows.Scan(&outletID, &stock); err != nil {
+			rows.Close()
+			return err
+		}
+		stocks[outletID] = stock
+		outletIDs = append(outletIDs, outletID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, outletID := range outletIDs {
+		lotID, err := findOrCreateLot(tx, product.ID, outletID, "", "")
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE stock_lots SET quantity = quantity + $1 WHERE id = $2", stocks[outletID], lotID)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// Delete buat hapus product
+// variantParent itu data produk induk yang dipakai buat ngisi varian
+type variantParent struct {
+	ID         int
+	Name       string
+	Price      int
+	Stock      int
+	CategoryID int
+	TaxRateID  int
+	TaxExempt  bool
+	Attributes []string
+}
+
+// lockVariantParent buat lock produk induk dan mastiin dia bukan varian dan punya atribut varian
+func lockVariantParent(tx *sql.Tx, id int, lock string) (*variantParent, error) {
+	var p variantParent
+	var parentID int
+	var attributes pq.StringArray
+	err := tx.QueryRow(
+		`SELECT id, COALESCE(parent_id, 0), name, price, stock, COALESCE(category_id, 0), COALESCE(tax_rate_id, 0), tax_exempt,
+		 COALESCE(variant_attributes, '{}')
+		 FROM products WHERE id = $1 `+lock,
+		id,
+	).Scan(&p.ID, &parentID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.TaxRateID, &p.TaxExempt, &attributes)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("parent product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if parentID != 0 {
+		return nil, errors.New("a variant cannot have its own variants")
+	}
+	if len(attributes) == 0 {
+		return nil, errors.New("parent product has no variant_attributes")
+	}
+	p.Attributes = attributes
+	return &p, nil
+}
+
+// applyVariantParent buat ngecek atribut varian sesuai atribut induknya dan belum dipakai varian lain,
+// lalu ngisi nama, kategori, pajak, dan harga varian dari induknya
+func applyVariantParent(tx *sql.Tx, v *models.Product, parent *variantParent) error {
+	if len(v.Attributes) != len(parent.Attributes) {
+		return errors.New("variant attributes must be exactly: " + strings.Join(parent.Attributes, ", "))
+	}
+	values := make([]string, 0, len(parent.Attributes))
+	for _, name := range parent.Attributes {
+		value, ok := v.Attributes[name]
+		if !ok || value == "" {
+			return errors.New("variant attribute " + name + " is required")
+		}
+		values = append(values, value)
+	}
+
+	attributes, err := marshalAttributes(v.Attributes)
+	if err != nil {
+		return err
+	}
+	var exists bool
+	err = tx.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM products WHERE parent_id = $1 AND attributes = $2::jsonb AND id <> $3)",
+		parent.ID, attributes, v.ID,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("a variant with the same attributes already exists")
+	}
+
+	v.VariantName = strings.Join(values, " / ")
+	v.Name = parent.Name + " - " + v.VariantName
+	v.CategoryID, v.TaxRateID, v.TaxExempt = parent.CategoryID, parent.TaxRateID, parent.TaxExempt
+	v.VariantAttributes = nil
+	if !v.PriceOverride {
+		v.Price = parent.Price
+	}
+	return nil
+}
+
+// marshalAttributes buat ubah atribut varian jadi JSON, nil kalau bukan varian
+func marshalAttributes(attributes map[string]string) (interface{}, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// nullStringArray buat nyimpen slice kosong sebagai NULL
+func nullStringArray(v []string) interface{} {
+	if len(v) == 0 {
+		return nil
+	}
+	return pq.Array(v)
+}
+
+// sameStrings buat ngecek dua daftar string isinya sama tanpa peduli urutan
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]string(nil), a...)
+	y := append([]string(nil), b...)
+	sort.Strings(x)
+	sort.Strings(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkProductCodes buat mastiin SKU dan barcode belum dipakai produk lain, biar errornya
+// jelas dan nggak cuma pelanggaran unique constraint. Constraint di DB tetep jadi pengaman terakhir
+func checkProductCodes(tx *sql.Tx, product *models.Product) error {
+	if product.SKU != "" {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE sku = $1 AND id <> $2)", product.SKU, product.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("sku " + product.SKU + " is already used by another product")
+		}
+	}
+
+	if len(product.Barcodes) > 0 {
+		var barcode string
+		err := tx.QueryRow(
+			"SELECT barcode FROM product_barcodes WHERE barcode = ANY($1) AND product_id <> $2 LIMIT 1",
+			pq.Array(product.Barcodes), product.ID,
+		).Scan(&barcode)
+		if err == nil {
+			return errors.New("barcode " + barcode + " is already used by another product")
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertProductBarcodes buat nyimpen barcode produk
+func insertProductBarcodes(tx *sql.Tx, productID int, barcodes []string) error {
+	for _, barcode := range barcodes {
+		_, err := tx.Exec("INSERT INTO product_barcodes (product_id, barcode) VALUES ($1, $2)", productID, barcode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceBundleComponents buat ganti isi paket. Komponen harus produk biasa atau varian,
+// bukan paket lain atau produk induk yang punya varian
+func replaceBundleComponents(tx *sql.Tx, bundle *models.Product) error {
+	if len(bundle.Components) == 0 {
+		return errors.New("bundle must have at least one component")
+	}
+
+	ids := make([]int64, 0, len(bundle.Components))
+	for _, c := range bundle.Components {
+		if c.ProductID == bundle.ID {
+			return errors.New("bundle cannot contain itself")
+		}
+		ids = append(ids, int64(c.ProductID))
+	}
+
+	rows, err := tx.Query(
+		`SELECT id, name, is_bundle, EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id)
+		 FROM products p WHERE id = ANY($1)`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	names := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var name string
+		var isBundle, hasVariants bool
+		if err := rows.Scan(&id, &name, &isBundle, &hasVariants); err != nil {
+			rows.Close()
+			return err
+		}
+		if isBundle {
+			rows.Close()
+			return errors.New("bundle component " + name + " cannot be another bundle")
+		}
+		if hasVariants {
+			rows.Close()
+			return errors.New("bundle component " + name + " has variants, choose a variant")
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM bundle_components WHERE bundle_id = $1", bundle.ID); err != nil {
+		return err
+	}
+	for i := range bundle.Components {
+		c := &bundle.Components[i]
+		name, ok := names[c.ProductID]
+		if !ok {
+			return fmt.Errorf("bundle component product id %d not found", c.ProductID)
+		}
+		c.ProductName = name
+		_, err := tx.Exec(
+			"INSERT INTO bundle_components (bundle_id, component_id, quantity) VALUES ($1, $2, $3)",
+			bundle.ID, c.ProductID, c.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadBundleTotals buat ngisi ulang stock, HPP dan isi paket setelah paketnya diupdate
+func loadBundleTotals(tx *sql.Tx, bundle *models.Product) error {
+	err := tx.QueryRow(
+		`SELECT COALESCE(MIN(cp.stock / bc.quantity), 0), COALESCE(SUM(cp.cost_price * bc.quantity), 0)
+		 FROM bundle_components bc
+		 JOIN products cp ON cp.id = bc.component_id
+		 WHERE bc.bundle_id = $1`,
+		bundle.ID,
+	).Scan(&bundle.Stock, &bundle.CostPrice)
+	if err != nil {
+		return err
+	}
+	if bundle.Components != nil {
+		return nil
+	}
+
+	rows, err := tx.Query(
+		`SELECT bc.component_id, cp.name, bc.quantity
+		 FROM bundle_components bc
+		 JOIN products cp ON cp.id = bc.component_id
+		 WHERE bc.bundle_id = $1
+		 ORDER BY bc.id`,
+		bundle.ID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	bundle.Components = make([]models.BundleComponent, 0)
+	for rows.Next() {
+		var c models.BundleComponent
+		if err := rows.Scan(&c.ProductID, &c.ProductName, &c.Quantity); err != nil {
+			return err
+		}
+		bundle.Components = append(bundle.Components, c)
+	}
+	return rows.Err()
+}
+
+// Delete buat hapus product. Produk induk yang masih punya varian, produk yang jadi komponen
+// paket, dan produk yang udah punya riwayat stock nggak bisa dihapus (ledger stock nggak boleh hilang)
 func (r *ProductRepository) Delete(id int) error {
+	var hasVariants, inBundle, hasStockHistory bool
+	err := r.db.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM products WHERE parent_id = $1),
+		 EXISTS (SELECT 1 FROM bundle_components WHERE component_id = $1),
+		 EXISTS (SELECT 1 FROM stock_movements WHERE product_id = $1)`,
+		id,
+	).Scan(&hasVariants, &inBundle, &hasStockHistory)
+	if err != nil {
+		return err
+	}
+	if hasVariants {
+		return errors.New("product still has variants, delete the variants first")
+	}
+	if inBundle {
+		return errors.New("product is used as a bundle component, remove it from the bundles first")
+	}
+	if hasStockHistory {
+		return errors.New("product has stock history and cannot be deleted")
+	}
+
 	query := "DELETE FROM products WHERE id = $1"
 	result, err := r.db.Exec(query, id)
 	if err != nil {
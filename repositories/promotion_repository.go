@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type PromotionRepository struct {
+	db *sql.DB
+}
+
+// NewPromotionRepository buat bikin instance repository baru
+func NewPromotionRepository(db *sql.DB) *PromotionRepository {
+	return &PromotionRepository{db: db}
+}
+
+const promotionColumns = `id, name, type, scope, COALESCE(product_id, 0), COALESCE(category_id, 0), value,
+	buy_quantity, get_quantity, min_spend, max_discount, starts_at, ends_at, active`
+
+// scanPromotion buat scan satu row promotions sesuai urutan promotionColumns
+func scanPromotion(row interface{ Scan(...interface{}) error }) (*models.Promotion, error) {
+	var p models.Promotion
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.Scope, &p.ProductID, &p.CategoryID, &p.Value,
+		&p.BuyQuantity, &p.GetQuantity, &p.MinSpend, &p.MaxDiscount, &startsAt, &endsAt, &p.Active)
+	if err != nil {
+		return nil, err
+	}
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	return &p, nil
+}
+
+// GetAll buat ambil semua promotions
+func (r *PromotionRepository) GetAll() ([]models.Promotion, error) {
+	return r.query("SELECT " + promotionColumns + " FROM promotions ORDER BY id")
+}
+
+// GetActive buat ambil promotions yang aktif dan lagi dalam masa berlaku
+func (r *PromotionRepository) GetActive() ([]models.Promotion, error) {
+	return r.query(`SELECT ` + promotionColumns + ` FROM promotions
+		WHERE active
+		  AND (starts_at IS NULL OR starts_at <= NOW())
+		  AND (ends_at IS NULL OR ends_at >= NOW())
+		ORDER BY id`)
+}
+
+func (r *PromotionRepository) query(query string, args ...interface{}) ([]models.Promotion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := make([]models.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+
+	return promotions, rows.Err()
+}
+
+// GetByID buat ambil promotion berdasarkan ID
+func (r *PromotionRepository) GetByID(id int) (*models.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRow("SELECT "+promotionColumns+" FROM promotions WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("promotion not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Create buat bikin promotion baru
+func (r *PromotionRepository) Create(p *models.Promotion) error {
+	query := `INSERT INTO promotions (name, type, scope, product_id, category_id, value, buy_quantity, get_quantity,
+			  min_spend, max_discount, starts_at, ends_at, active)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id`
+	err := r.db.QueryRow(query, p.Name, p.Type, p.Scope, nullInt(p.ProductID), nullInt(p.CategoryID), p.Value,
+		p.BuyQuantity, p.GetQuantity, p.MinSpend, p.MaxDiscount, p.StartsAt, p.EndsAt, p.Active).Scan(&p.ID)
+	return err
+}
+
+// Update buat update promotion yang udah ada
+func (r *PromotionRepository) Update(p *models.Promotion) error {
+	query := `UPDATE promotions SET name = $1, type = $2, scope = $3, product_id = $4, category_id = $5, value = $6,
+			  buy_quantity = $7, get_quantity = $8, min_spend = $9, max_discount = $10, starts_at = $11, ends_at = $12,
+			  active = $13
+			  WHERE id = $14`
+	result, err := r.db.Exec(query, p.Name, p.Type, p.Scope, nullInt(p.ProductID), nullInt(p.CategoryID), p.Value,
+		p.BuyQuantity, p.GetQuantity, p.MinSpend, p.MaxDiscount, p.StartsAt, p.EndsAt, p.Active, p.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("promotion not found")
+	}
+
+	return nil
+}
+
+// Delete buat hapus promotion
+func (r *PromotionRepository) Delete(id int) error {
+	result, err := r.db.Exec("DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("promotion not found")
+	}
+
+	return nil
+}
+
+// nullInt buat nyimpen ID opsional, 0 disimpan sebagai NULL
+func nullInt(v int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(v), Valid: v != 0}
+}
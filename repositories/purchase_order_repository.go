@@ -0,0 +1,486 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+type PurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+// NewPurchaseOrderRepository buat bikin instance repository baru
+func NewPurchaseOrderRepository(db *sql.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, COALESCE(po.outlet_id, 0), COALESCE(o.name, ''), po.status, po.note, po.created_at`
+
+// GetAll buat ambil semua purchase order beserta item-nya, bisa difilter status
+func (r *PurchaseOrderRepository) GetAll(status string) ([]models.PurchaseOrder, error) {
+	query := `SELECT ` + purchaseOrderColumns + `
+			  FROM purchase_orders po
+			  JOIN suppliers s ON s.id = po.supplier_id
+			  LEFT JOIN outlets o ON o.id = po.outlet_id`
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE po.status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY po.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]models.PurchaseOrder, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var po models.PurchaseOrder
+		err := rows.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.OutletID, &po.OutletName, &po.Status, &po.Note, &po.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		po.Items = make([]models.PurchaseOrderItem, 0)
+		orders = append(orders, po)
+		ids = append(ids, int64(po.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		orders[i].Items = append(orders[i].Items, items[orders[i].ID]...)
+	}
+
+	return orders, nil
+}
+
+// GetByID buat ambil purchase order beserta item dan dokumen penerimaannya
+func (r *PurchaseOrderRepository) GetByID(id int) (*models.PurchaseOrder, error) {
+	query := `SELECT ` + purchaseOrderColumns + `
+			  FROM purchase_orders po
+			  JOIN suppliers s ON s.id = po.supplier_id
+			  LEFT JOIN outlets o ON o.id = po.outlet_id
+			  WHERE po.id = $1`
+
+	var po models.PurchaseOrder
+	err := r.db.QueryRow(query, id).Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.OutletID, &po.OutletName, &po.Status, &po.Note, &po.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("purchase order not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems([]int64{int64(id)})
+	if err != nil {
+		return nil, err
+	}
+	po.Items = append(make([]models.PurchaseOrderItem, 0), items[id]...)
+
+	po.Receipts, err = r.getReceipts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+// getItems buat ambil item beberapa purchase order sekaligus, dikelompokkan per purchase_order_id
+func (r *PurchaseOrderRepository) getItems(purchaseOrderIDs []int64) (map[int][]models.PurchaseOrderItem, error) {
+	result := make(map[int][]models.PurchaseOrderItem)
+	if len(purchaseOrderIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT i.id, i.purchase_order_id, i.product_id, p.name, i.quantity, i.unit_cost, i.received_quantity
+		 FROM purchase_order_items i
+		 JOIN products p ON p.id = i.product_id
+		 WHERE i.purchase_order_id = ANY($1)
+		 ORDER BY i.id`,
+		pq.Array(purchaseOrderIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i models.PurchaseOrderItem
+		err := rows.Scan(&i.ID, &i.PurchaseOrderID, &i.ProductID, &i.ProductName, &i.Quantity, &i.UnitCost, &i.ReceivedQuantity)
+		if err != nil {
+			return nil, err
+		}
+		result[i.PurchaseOrderID] = append(result[i.PurchaseOrderID], i)
+	}
+
+	return result, rows.Err()
+}
+
+// getReceipts buat ambil semua dokumen penerimaan barang satu purchase order
+func (r *PurchaseOrderRepository) getReceipts(purchaseOrderID int) ([]models.GoodsReceipt, error) {
+	rows, err := r.db.Query(
+		`SELECT gr.id, gr.purchase_order_id, gr.received_by, gr.note, gr.received_at,
+		 gri.id, gri.purchase_order_item_id, gri.product_id, gri.quantity, gri.unit_cost, gri.unit, gri.unit_quantity,
+		 gri.batch_number, COALESCE(TO_CHAR(gri.expiry_date, 'YYYY-MM-DD'), '')
+		 FROM goods_receipts gr
+		 JOIN goods_receipt_items gri ON gri.goods_receipt_id = gr.id
+		 WHERE gr.purchase_order_id = $1
+		 ORDER BY gr.id, gri.id`,
+		purchaseOrderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	receipts := make([]models.GoodsReceipt, 0)
+	indexByID := make(map[int]int)
+	for rows.Next() {
+		var gr models.GoodsReceipt
+		var item models.GoodsReceiptItem
+		err := rows.Scan(&gr.ID, &gr.PurchaseOrderID, &gr.ReceivedBy, &gr.Note, &gr.ReceivedAt,
+			&item.ID, &item.PurchaseOrderItemID, &item.ProductID, &item.Quantity, &item.UnitCost, &item.Unit, &item.UnitQuantity,
+			&item.BatchNumber, &item.ExpiryDate)
+		if err != nil {
+			return nil, err
+		}
+		item.GoodsReceiptID = gr.ID
+
+		i, ok := indexByID[gr.ID]
+		if !ok {
+			gr.Items = make([]models.GoodsReceiptItem, 0)
+			receipts = append(receipts, gr)
+			i = len(receipts) - 1
+			indexByID[gr.ID] = i
+		}
+		receipts[i].Items = append(receipts[i].Items, item)
+	}
+
+	return receipts, rows.Err()
+}
+
+// Create buat bikin purchase order baru dengan status draft
+func (r *PurchaseOrderRepository) Create(po *models.PurchaseOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	po.OutletID, po.OutletName, err = resolveOutlet(tx, po.OutletID)
+	if err != nil {
+		return err
+	}
+
+	po.Status = models.PurchaseOrderStatusDraft
+	err = tx.QueryRow(
+		"INSERT INTO purchase_orders (supplier_id, outlet_id, status, note) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		po.SupplierID, po.OutletID, po.Status, po.Note,
+	).Scan(&po.ID, &po.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertPurchaseOrderItems(tx, po); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update buat ganti supplier, outlet penerima, catatan, dan item purchase order. Cuma boleh selama masih draft
+func (r *PurchaseOrderRepository) Update(po *models.PurchaseOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, po.ID)
+	if err != nil {
+		return err
+	}
+	if status != models.PurchaseOrderStatusDraft {
+		return fmt.Errorf("cannot update purchase order with status %s", status)
+	}
+
+	po.OutletID, po.OutletName, err = resolveOutlet(tx, po.OutletID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		"UPDATE purchase_orders SET supplier_id = $1, outlet_id = $2, note = $3 WHERE id = $4 RETURNING status, created_at",
+		po.SupplierID, po.OutletID, po.Note, po.ID,
+	).Scan(&po.Status, &po.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM purchase_order_items WHERE purchase_order_id = $1", po.ID); err != nil {
+		return err
+	}
+	if err := insertPurchaseOrderItems(tx, po); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func insertPurchaseOrderItems(tx *sql.Tx, po *models.PurchaseOrder) error {
+	for i := range po.Items {
+		item := &po.Items[i]
+		item.PurchaseOrderID = po.ID
+		item.ReceivedQuantity = 0
+		err := tx.QueryRow(
+			"INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity, unit_cost) VALUES ($1, $2, $3, $4) RETURNING id",
+			po.ID, item.ProductID, item.Quantity, item.UnitCost,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockPurchaseOrder buat lock row purchase order dan balikin status sekarangnya
+func lockPurchaseOrder(tx *sql.Tx, id int) (string, error) {
+	var status string
+	err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", errors.New("purchase order not found")
+	}
+	return status, err
+}
+
+// UpdateStatus buat pindah status purchase order, cuma boleh dari salah satu status di from
+func (r *PurchaseOrderRepository) UpdateStatus(id int, from []string, to string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return err
+	}
+
+	allowed := false
+	for _, s := range from {
+		if s == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot change purchase order status from %s to %s", status, to)
+	}
+
+	if _, err := tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", to, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive buat nyatet penerimaan barang dari purchase order. Stock produk di outlet purchase order nambah
+// sesuai jumlah yang diterima dan tercatat di ledger, semuanya dalam satu DB transaction
+func (r *PurchaseOrderRepository) Receive(id int, req models.ReceiveRequest) (*models.GoodsReceipt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status, err := lockPurchaseOrder(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status != models.PurchaseOrderStatusSent && status != models.PurchaseOrderStatusPartiallyReceived {
+		return nil, fmt.Errorf("cannot receive goods for purchase order with status %s", status)
+	}
+
+	var outletID int
+	if err := tx.QueryRow("SELECT COALESCE(outlet_id, 0) FROM purchase_orders WHERE id = $1", id).Scan(&outletID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(
+		`SELECT i.id, i.product_id, p.name, i.quantity, i.unit_cost, i.received_quantity, p.base_unit
+		 FROM purchase_order_items i
+		 JOIN products p ON p.id = i.product_id
+		 WHERE i.purchase_order_id = $1`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	poItems := make(map[int]*models.PurchaseOrderItem)
+	baseUnits := make(map[int]string)
+	productIDs := make([]int64, 0)
+	for rows.Next() {
+		var i models.PurchaseOrderItem
+		var baseUnit string
+		if err := rows.Scan(&i.ID, &i.ProductID, &i.ProductName, &i.Quantity, &i.UnitCost, &i.ReceivedQuantity, &baseUnit); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		poItems[i.ID] = &i
+		baseUnits[i.ProductID] = baseUnit
+		productIDs = append(productIDs, int64(i.ProductID))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	productUnits, err := getProductUnits(tx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.GoodsReceipt{
+		PurchaseOrderID: id,
+		ReceivedBy:      req.ReceivedBy,
+		Note:            req.Note,
+		Items:           make([]models.GoodsReceiptItem, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		poItem, ok := poItems[item.PurchaseOrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("purchase order item id %d not found", item.PurchaseOrderItemID)
+		}
+
+		// Barang boleh diterima pakai satuan apa aja (misalnya box), stock dan harga pokok tetap di satuan dasar
+		baseUnit := baseUnits[poItem.ProductID]
+		unit, err := findUnit(baseUnit, 0, productUnits[poItem.ProductID], item.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", poItem.ProductName, err)
+		}
+		quantity, err := toBaseQuantity(item.Quantity, unit, baseUnit)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", poItem.ProductName, err)
+		}
+
+		if poItem.ReceivedQuantity+quantity > poItem.Quantity {
+			return nil, fmt.Errorf("cannot receive %d %s for purchase order item id %d (remaining: %d %s)",
+				quantity, baseUnit, poItem.ID, poItem.Quantity-poItem.ReceivedQuantity, baseUnit)
+		}
+		poItem.ReceivedQuantity += quantity
+
+		unitCost := poItem.UnitCost
+		if item.UnitCost != 0 {
+			unitCost = int(math.Round(float64(item.UnitCost) / float64(unit.Factor)))
+		}
+		receipt.Items = append(receipt.Items, models.GoodsReceiptItem{
+			PurchaseOrderItemID: poItem.ID,
+			ProductID:           poItem.ProductID,
+			Quantity:            quantity,
+			UnitCost:            unitCost,
+			Unit:                unit.Name,
+			UnitQuantity:        item.Quantity,
+			BatchNumber:         item.BatchNumber,
+			ExpiryDate:          item.ExpiryDate,
+		})
+	}
+
+	err = tx.QueryRow(
+		"INSERT INTO goods_receipts (purchase_order_id, received_by, note) VALUES ($1, $2, $3) RETURNING id, received_at",
+		id, receipt.ReceivedBy, receipt.Note,
+	).Scan(&receipt.ID, &receipt.ReceivedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock ditambah urut product ID, sama kayak urutan lock di checkout biar nggak deadlock
+	sort.SliceStable(receipt.Items, func(i, j int) bool { return receipt.Items[i].ProductID < receipt.Items[j].ProductID })
+	for i := range receipt.Items {
+		item := &receipt.Items[i]
+		item.GoodsReceiptID = receipt.ID
+		err = tx.QueryRow(
+			`INSERT INTO goods_receipt_items (goods_receipt_id, purchase_order_item_id, product_id, quantity, unit_cost, unit, unit_quantity,
+			 batch_number, expiry_date)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::date) RETURNING id`,
+			receipt.ID, item.PurchaseOrderItemID, item.ProductID, item.Quantity, item.UnitCost, item.Unit, item.UnitQuantity,
+			item.BatchNumber, item.ExpiryDate,
+		).Scan(&item.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(
+			"UPDATE purchase_order_items SET received_quantity = received_quantity + $1 WHERE id = $2",
+			item.Quantity, item.PurchaseOrderItemID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// Harga pokok dihitung ulang sebelum stock-nya nambah, rata-rata tertimbang stock lama dan barang masuk
+		if err := updateWeightedAverageCost(tx, item.ProductID, item.Quantity, item.UnitCost); err != nil {
+			return nil, err
+		}
+
+		err = moveStock(tx, &models.StockMovement{
+			ProductID:     item.ProductID,
+			OutletID:      outletID,
+			Quantity:      item.Quantity,
+			Reason:        models.StockReasonReceiving,
+			ReferenceType: models.StockReferenceReceipt,
+			ReferenceID:   receipt.ID,
+			Note:          fmt.Sprintf("PO #%d", id),
+			CreatedBy:     receipt.ReceivedBy,
+			BatchNumber:   item.BatchNumber,
+			ExpiryDate:    item.ExpiryDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newStatus := models.PurchaseOrderStatusReceived
+	for _, poItem := range poItems {
+		if poItem.ReceivedQuantity < poItem.Quantity {
+			newStatus = models.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	if _, err := tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", newStatus, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// updateWeightedAverageCost buat ngitung ulang cost_price produk pakai rata-rata tertimbang:
+// (stock * cost_price + qty * unit_cost) / (stock + qty). Kalau stock lama kosong atau minus, langsung pakai unit_cost
+func updateWeightedAverageCost(tx *sql.Tx, productID, quantity, unitCost int) error {
+	_, err := tx.Exec(
+		`UPDATE products SET cost_price = CASE
+			WHEN stock <= 0 THEN $1
+			ELSE ROUND((stock::NUMERIC * cost_price + $2::NUMERIC * $1) / (stock + $2))
+		 END
+		 WHERE id = $3`,
+		unitCost, quantity, productID,
+	)
+	return err
+}
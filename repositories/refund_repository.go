@@ -0,0 +1,515 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+type RefundRepository struct {
+	db *sql.DB
+}
+
+// NewRefundRepository buat bikin instance repository baru
+func NewRefundRepository(db *sql.DB) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+// refundableLine itu sisa item per baris transaksi yang masih bisa di-refund
+type refundableLine struct {
+	detailID              int
+	productID             int
+	productName           string
+	quantity              int
+	total                 int
+	taxAmount             int
+	serviceChargeAmount   int
+	refundedQty           int
+	refundedAmount        int
+	refundedTax           int
+	refundedServiceCharge int
+	requestedRefund       int
+}
+
+// CreateRefund buat bikin dokumen void/refund, balikin stock produk, dan update status transaksi
+// dalam satu DB transaction. Kalau req.Items kosong, semua sisa item yang belum di-refund ikut di-refund
+func (r *RefundRepository) CreateRefund(transactionID int, refundType string, req models.RefundRequest) (*models.Refund, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock transaksinya biar dua refund barengan nggak bisa ngelewatin qty yang dijual
+	var status string
+	var outletID int
+	err = tx.QueryRow("SELECT status, COALESCE(outlet_id, 0) FROM transactions WHERE id = $1 FOR UPDATE", transactionID).Scan(&status, &outletID)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status == models.TransactionStatusVoided {
+		return nil, errors.New("transaction already voided")
+	}
+
+	rows, err := tx.Query(`
+		SELECT td.id, COALESCE(td.product_id, 0), td.product_name, td.quantity,
+			   td.total, td.tax_amount, td.service_charge_amount,
+			   COALESCE(SUM(rd.quantity), 0), COALESCE(SUM(rd.amount), 0),
+			   COALESCE(SUM(rd.tax_amount), 0), COALESCE(SUM(rd.service_charge_amount), 0)
+		FROM transaction_details td
+		LEFT JOIN refund_details rd ON rd.transaction_detail_id = td.id
+		WHERE td.transaction_id = $1
+		GROUP BY td.id
+		ORDER BY td.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*refundableLine, 0)
+	linesByID := make(map[int]*refundableLine)
+	for rows.Next() {
+		l := &refundableLine{}
+		err := rows.Scan(&l.detailID, &l.productID, &l.productName, &l.quantity,
+			&l.total, &l.taxAmount, &l.serviceChargeAmount,
+			&l.refundedQty, &l.refundedAmount, &l.refundedTax, &l.refundedServiceCharge)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines = append(lines, l)
+		linesByID[l.detailID] = l
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(req.Items) == 0 {
+		for _, l := range lines {
+			l.requestedRefund = l.quantity - l.refundedQty
+		}
+	} else {
+		for _, item := range req.Items {
+			l, ok := linesByID[item.TransactionDetailID]
+			if !ok {
+				return nil, fmt.Errorf("transaction detail id %d not found in transaction %d", item.TransactionDetailID, transactionID)
+			}
+			if item.Quantity <= 0 {
+				return nil, fmt.Errorf("quantity for transaction detail id %d must be greater than 0", item.TransactionDetailID)
+			}
+			l.requestedRefund += item.Quantity
+		}
+	}
+
+	refund := &models.Refund{
+		TransactionID: transactionID,
+		Type:          refundType,
+		Reason:        req.Reason,
+		Details:       make([]models.RefundDetail, 0),
+	}
+
+	fullyRefunded := true
+	for _, l := range lines {
+		remaining := l.quantity - l.refundedQty
+		if l.requestedRefund > remaining {
+			return nil, fmt.Errorf("cannot refund %d of %s (remaining refundable: %d)", l.requestedRefund, l.productName, remaining)
+		}
+		if l.requestedRefund < remaining {
+			fullyRefunded = false
+		}
+		if l.requestedRefund == 0 {
+			continue
+		}
+
+		// Nilai refund proporsional dari total baris (udah termasuk pajak dan service charge).
+		// Baris yang di-refund habis ambil sisanya biar pembulatan nggak nyisa
+		amount := l.total * l.requestedRefund / l.quantity
+		tax := l.taxAmount * l.requestedRefund / l.quantity
+		serviceCharge := l.serviceChargeAmount * l.requestedRefund / l.quantity
+		if l.requestedRefund == remaining {
+			amount = l.total - l.refundedAmount
+			tax = l.taxAmount - l.refundedTax
+			serviceCharge = l.serviceChargeAmount - l.refundedServiceCharge
+		}
+
+		refund.TotalAmount += amount
+		refund.Details = append(refund.Details, models.RefundDetail{
+			TransactionDetailID: l.detailID,
+			ProductID:           l.productID,
+			ProductName:         l.productName,
+			Quantity:            l.requestedRefund,
+			Amount:              amount,
+			TaxAmount:           tax,
+			ServiceChargeAmount: serviceCharge,
+		})
+	}
+
+	if len(refund.Details) == 0 {
+		return nil, errors.New("nothing left to refund in this transaction")
+	}
+
+	var createdAt time.Time
+	err = tx.QueryRow(
+		"INSERT INTO refunds (transaction_id, type, reason, total_amount) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		transactionID, refund.Type, refund.Reason, refund.TotalAmount,
+	).Scan(&refund.ID, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+	refund.CreatedAt = createdAt
+
+	for i := range refund.Details {
+		d := &refund.Details[i]
+		d.RefundID = refund.ID
+
+		err = tx.QueryRow(
+			`INSERT INTO refund_details (refund_id, transaction_detail_id, product_id, quantity, amount, tax_amount, service_charge_amount)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+			refund.ID, d.TransactionDetailID, nullInt(d.ProductID), d.Quantity, d.Amount, d.TaxAmount, d.ServiceChargeAmount,
+		).Scan(&d.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Paket dibalikin ke stock komponennya sesuai isi paket waktu checkout
+	detailIDs := make([]int64, 0, len(refund.Details))
+	for _, d := range refund.Details {
+		detailIDs = append(detailIDs, int64(d.TransactionDetailID))
+	}
+	components, err := getRefundComponents(tx, detailIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock dibalikin urut product ID, sama kayak urutan lock di checkout biar nggak deadlock
+	restock := make(map[int]int)
+	productIDs := make([]int, 0)
+	addRestock := func(productID, quantity int) {
+		if productID == 0 {
+			return
+		}
+		if _, ok := restock[productID]; !ok {
+			productIDs = append(productIDs, productID)
+		}
+		restock[productID] += quantity
+	}
+	for _, d := range refund.Details {
+		if bundle, ok := components[d.TransactionDetailID]; ok {
+			for _, c := range bundle {
+				addRestock(c.ProductID, c.Quantity*d.Quantity)
+			}
+			continue
+		}
+		addRestock(d.ProductID, d.Quantity)
+	}
+	sort.Ints(productIDs)
+	for _, productID := range productIDs {
+		lotID, err := soldLot(tx, transactionID, productID)
+		if err != nil {
+			return nil, err
+		}
+		// Barangnya balik ke stock outlet tempat transaksinya terjadi
+		err = moveStock(tx, &models.StockMovement{
+			ProductID:     productID,
+			OutletID:      outletID,
+			Quantity:      restock[productID],
+			Reason:        models.StockReasonRefund,
+			ReferenceType: models.StockReferenceRefund,
+			ReferenceID:   refund.ID,
+			Note:          refund.Reason,
+			LotID:         lotID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := settleRefundPoints(tx, transactionID, refund, req.PointsExpiryDays); err != nil {
+		return nil, err
+	}
+	if err := settleRefundGiftCards(tx, transactionID, refund); err != nil {
+		return nil, err
+	}
+
+	newStatus := models.TransactionStatusPartiallyRefunded
+	if refundType == models.RefundTypeVoid {
+		newStatus = models.TransactionStatusVoided
+	} else if fullyRefunded {
+		newStatus = models.TransactionStatusRefunded
+	}
+	_, err = tx.Exec("UPDATE transactions SET status = $1 WHERE id = $2", newStatus, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// settleRefundPoints buat ngitung ulang poin loyalty transaksi member setelah refund di-insert.
+// Poin yang ditukar dibalikin dan poin yang didapet ditarik lagi sesuai proporsi total yang udah di-refund,
+// dihitung kumulatif biar refund sebagian-sebagian nggak nyisa pembulatan. Poin yang ditarik nggak bisa
+// bikin saldo minus, kalau saldonya udah kepake cuma yang masih ada yang ditarik
+func settleRefundPoints(tx *sql.Tx, transactionID int, refund *models.Refund, expiryDays int) error {
+	var customerID, totalAmount, redeemed, earned int
+	err := tx.QueryRow(
+		"SELECT COALESCE(customer_id, 0), total_amount, points_redeemed, points_earned FROM transactions WHERE id = $1",
+		transactionID,
+	).Scan(&customerID, &totalAmount, &redeemed, &earned)
+	if err != nil || customerID == 0 || (redeemed == 0 && earned == 0) {
+		return err
+	}
+
+	var refundedAmount, restored, clawedBack int
+	err = tx.QueryRow(
+		`SELECT COALESCE(SUM(total_amount), 0), COALESCE(SUM(points_restored), 0), COALESCE(SUM(points_clawed_back), 0)
+		 FROM refunds WHERE transaction_id = $1`,
+		transactionID,
+	).Scan(&refundedAmount, &restored, &clawedBack)
+	if err != nil {
+		return err
+	}
+	share := func(points int) int {
+		if refundedAmount >= totalAmount {
+			return points
+		}
+		return points * refundedAmount / totalAmount
+	}
+
+	if err := lockCustomer(tx, customerID); err != nil {
+		return err
+	}
+	if _, err := expireCustomerPoints(tx, customerID); err != nil {
+		return err
+	}
+
+	if restore := share(redeemed) - restored; restore > 0 {
+		err := movePoints(tx, &models.PointEntry{
+			CustomerID:    customerID,
+			Points:        restore,
+			Reason:        models.PointReasonRestore,
+			TransactionID: transactionID,
+			RefundID:      refund.ID,
+		}, expiryDays)
+		if err != nil {
+			return err
+		}
+		refund.PointsRestored = restore
+	}
+
+	if clawback := share(earned) - clawedBack; clawback > 0 {
+		var balance int
+		if err := tx.QueryRow("SELECT points FROM customers WHERE id = $1", customerID).Scan(&balance); err != nil {
+			return err
+		}
+		if clawback = min(clawback, balance); clawback > 0 {
+			err := movePoints(tx, &models.PointEntry{
+				CustomerID:    customerID,
+				Points:        -clawback,
+				Reason:        models.PointReasonClawback,
+				TransactionID: transactionID,
+				RefundID:      refund.ID,
+			}, 0)
+			if err != nil {
+				return err
+			}
+			refund.PointsClawedBack = clawback
+		}
+	}
+
+	_, err = tx.Exec(
+		"UPDATE refunds SET points_restored = $1, points_clawed_back = $2 WHERE id = $3",
+		refund.PointsRestored, refund.PointsClawedBack, refund.ID,
+	)
+	return err
+}
+
+// settleRefundGiftCards buat balikin saldo gift card yang dipakai bayar transaksi ini setelah refund di-insert.
+// Refund dibalikin ke gift card duluan (sisanya lewat metode lain di kasir) sampai sebesar yang dibayar pakai
+// gift card, dihitung kumulatif dari semua refund transaksi ini. Gift card di-lock urut ID kayak di checkout,
+// setelah produk dan customer
+func settleRefundGiftCards(tx *sql.Tx, transactionID int, refund *models.Refund) error {
+	rows, err := tx.Query(
+		`SELECT tp.gift_card_id, SUM(tp.amount),
+		 COALESCE((SELECT SUM(e.amount) FROM gift_card_entries e
+		           WHERE e.gift_card_id = tp.gift_card_id AND e.transaction_id = $1 AND e.reason = $2), 0)
+		 FROM transaction_payments tp
+		 WHERE tp.transaction_id = $1 AND tp.gift_card_id IS NOT NULL
+		 GROUP BY tp.gift_card_id
+		 ORDER BY tp.gift_card_id`,
+		transactionID, models.GiftCardReasonRefund,
+	)
+	if err != nil {
+		return err
+	}
+	type giftCardPayment struct{ id, paid, refunded int }
+	payments := make([]giftCardPayment, 0)
+	paid, refunded := 0, 0
+	for rows.Next() {
+		var p giftCardPayment
+		if err := rows.Scan(&p.id, &p.paid, &p.refunded); err != nil {
+			rows.Close()
+			return err
+		}
+		payments = append(payments, p)
+		paid += p.paid
+		refunded += p.refunded
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(payments) == 0 {
+		return nil
+	}
+
+	var refundedAmount int
+	err = tx.QueryRow("SELECT COALESCE(SUM(total_amount), 0) FROM refunds WHERE transaction_id = $1", transactionID).Scan(&refundedAmount)
+	if err != nil {
+		return err
+	}
+
+	credit := min(refundedAmount, paid) - refunded
+	for _, p := range payments {
+		if credit <= 0 {
+			break
+		}
+		amount := min(credit, p.paid-p.refunded)
+		if amount <= 0 {
+			continue
+		}
+		err := moveGiftCardBalance(tx, &models.GiftCardEntry{
+			GiftCardID:    p.id,
+			Amount:        amount,
+			Reason:        models.GiftCardReasonRefund,
+			TransactionID: transactionID,
+			RefundID:      refund.ID,
+		})
+		if err != nil {
+			return err
+		}
+		refund.GiftCardRefunded += amount
+		credit -= amount
+	}
+
+	_, err = tx.Exec("UPDATE refunds SET gift_card_refunded = $1 WHERE id = $2", refund.GiftCardRefunded, refund.ID)
+	return err
+}
+
+// soldLot buat nyari lot produk track_lots yang kejual di transaksi ini, barang refund dibalikin ke situ.
+// Kalau kejual dari beberapa lot, diambil yang kadaluarsanya paling lama. Balikin 0 kalau nggak ada
+func soldLot(tx *sql.Tx, transactionID, productID int) (int, error) {
+	var lotID int
+	err := tx.QueryRow(
+		`SELECT ml.lot_id
+		 FROM stock_movement_lots ml
+		 JOIN stock_movements m ON m.id = ml.stock_movement_id
+		 JOIN stock_lots l ON l.id = ml.lot_id
+		 WHERE m.reference_type = $1 AND m.reference_id = $2 AND m.product_id = $3 AND ml.quantity < 0
+		 ORDER BY l.expiry_date DESC NULLS FIRST, l.id DESC
+		 LIMIT 1`,
+		models.StockReferenceTransaction, transactionID, productID,
+	).Scan(&lotID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return lotID, err
+}
+
+// getRefundComponents buat ambil isi paket dari detail transaksi yang direfund, per transaction_detail_id
+func getRefundComponents(tx *sql.Tx, detailIDs []int64) (map[int][]models.TransactionDetailComponent, error) {
+	rows, err := tx.Query(
+		`SELECT transaction_detail_id, COALESCE(product_id, 0), product_name, quantity, unit_cost
+		 FROM transaction_detail_components
+		 WHERE transaction_detail_id = ANY($1)
+		 ORDER BY id`,
+		pq.Array(detailIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.TransactionDetailComponent)
+	for rows.Next() {
+		var detailID int
+		var c models.TransactionDetailComponent
+		if err := rows.Scan(&detailID, &c.ProductID, &c.ProductName, &c.Quantity, &c.UnitCost); err != nil {
+			return nil, err
+		}
+		components[detailID] = append(components[detailID], c)
+	}
+	return components, rows.Err()
+}
+
+// GetByTransactionID buat ambil semua dokumen refund milik satu transaksi
+func (r *RefundRepository) GetByTransactionID(transactionID int) ([]models.Refund, error) {
+	rows, err := r.db.Query(
+		`SELECT id, transaction_id, type, reason, total_amount, points_restored, points_clawed_back, gift_card_refunded, created_at
+		 FROM refunds WHERE transaction_id = $1 ORDER BY id`,
+		transactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := make([]models.Refund, 0)
+	indexByID := make(map[int]int)
+	for rows.Next() {
+		var rf models.Refund
+		err := rows.Scan(&rf.ID, &rf.TransactionID, &rf.Type, &rf.Reason, &rf.TotalAmount, &rf.PointsRestored, &rf.PointsClawedBack, &rf.GiftCardRefunded, &rf.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rf.Details = make([]models.RefundDetail, 0)
+		indexByID[rf.ID] = len(refunds)
+		refunds = append(refunds, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(refunds) == 0 {
+		return refunds, nil
+	}
+
+	detailRows, err := r.db.Query(`
+		SELECT rd.id, rd.refund_id, rd.transaction_detail_id, COALESCE(rd.product_id, 0), td.product_name, rd.quantity, rd.amount,
+			   rd.tax_amount, rd.service_charge_amount
+		FROM refund_details rd
+		JOIN refunds rf ON rd.refund_id = rf.id
+		JOIN transaction_details td ON rd.transaction_detail_id = td.id
+		WHERE rf.transaction_id = $1
+		ORDER BY rd.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer detailRows.Close()
+
+	for detailRows.Next() {
+		var d models.RefundDetail
+		err := detailRows.Scan(&d.ID, &d.RefundID, &d.TransactionDetailID, &d.ProductID, &d.ProductName, &d.Quantity, &d.Amount,
+			&d.TaxAmount, &d.ServiceChargeAmount)
+		if err != nil {
+			return nil, err
+		}
+		i := indexByID[d.RefundID]
+		refunds[i].Details = append(refunds[i].Details, d)
+	}
+
+	return refunds, detailRows.Err()
+}
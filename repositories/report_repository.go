@@ -2,6 +2,9 @@ package repositories
 
 import (
 	"database/sql"
+	"fmt"
+	"math"
+	"sort"
 
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
 )
@@ -15,34 +18,93 @@ func NewReportRepository(db *sql.DB) *ReportRepository {
 	return &ReportRepository{db: db}
 }
 
-// GetDailySales buat ambil laporan penjualan hari ini
-func (r *ReportRepository) GetDailySales() (*models.DailySalesReport, error) {
-	report := &models.DailySalesReport{}
-
-	// Query untuk total revenue dan total transaksi hari ini
-	queryTotal := `
-		SELECT COALESCE(SUM(total_amount), 0), COUNT(*)
-		FROM transactions
-		WHERE DATE(created_at) = CURRENT_DATE
-	`
-	err := r.db.QueryRow(queryTotal).Scan(&report.TotalRevenue, &report.TotalTransaksi)
+// GetDailySales buat ambil laporan penjualan hari ini, outletID 0 berarti semua outlet
+func (r *ReportRepository) GetDailySales(outletID int) (*models.DailySalesReport, error) {
+	return r.getSalesReport("CURRENT_DATE", "CURRENT_DATE", outletID)
+}
+
+// GetReportByDateRange buat ambil laporan penjualan berdasarkan range tanggal, outletID 0 berarti semua outlet
+func (r *ReportRepository) GetReportByDateRange(startDate, endDate string, outletID int) (*models.DailySalesReport, error) {
+	return r.getSalesReport("$1", "$2", outletID, startDate, endDate)
+}
+
+// getSalesReport buat nyusun laporan penjualan di antara startExpr dan endExpr (ekspresi SQL, misalnya
+// CURRENT_DATE atau placeholder $1). Revenue dan qty terjual udah dikurangi refund yang terjadi di periode yang sama.
+// Kalau outletID diisi cuma transaksi outlet itu (dan refund-nya) yang dihitung
+func (r *ReportRepository) getSalesReport(startExpr, endExpr string, outletID int, args ...interface{}) (*models.DailySalesReport, error) {
+	report := &models.DailySalesReport{OutletID: outletID}
+	args = append(args, outletID)
+	outletExpr := fmt.Sprintf("$%d", len(args))
+
+	// inRange buat kondisi transaksi (alias t), inRefundRange buat kondisi refund (alias rf)
+	inRange := func(column string) string {
+		return fmt.Sprintf("DATE(%s) >= %s AND DATE(%s) <= %s AND (%s = 0 OR t.outlet_id = %s)",
+			column, startExpr, column, endExpr, outletExpr, outletExpr)
+	}
+	inRefundRange := func(column string) string {
+		return fmt.Sprintf("DATE(%s) >= %s AND DATE(%s) <= %s AND (%s = 0 OR rf.transaction_id IN (SELECT id FROM transactions WHERE outlet_id = %s))",
+			column, startExpr, column, endExpr, outletExpr, outletExpr)
+	}
+
+	// Query untuk revenue kotor, diskon, pajak, service charge, refund, dan total transaksi yang nggak di-void.
+	// Pajak dan service charge yang ikut di-refund dikurangi dari yang terkumpul. Refund yang balik ke
+	// gift card dipisah biar breakdown metode pembayaran bisa dicocokkan ke revenue bersih
+	queryTotal := fmt.Sprintf(`
+		WITH sales AS (
+			SELECT COALESCE(SUM(gross_amount), 0) AS gross, COALESCE(SUM(discount_amount), 0) AS discount,
+				   COALESCE(SUM(tax_amount), 0) AS tax, COALESCE(SUM(service_charge_amount), 0) AS service_charge,
+				   COALESCE(SUM(total_amount), 0) AS total,
+				   COUNT(*) FILTER (WHERE status <> '%s') AS transaksi
+			FROM transactions t
+			WHERE %s
+		), returns AS (
+			SELECT COALESCE(SUM(rd.amount), 0) AS total, COALESCE(SUM(rd.tax_amount), 0) AS tax,
+				   COALESCE(SUM(rd.service_charge_amount), 0) AS service_charge
+			FROM refund_details rd
+			JOIN refunds rf ON rd.refund_id = rf.id
+			WHERE %s
+		)
+		), gift_card_returns AS (
+			SELECT COALESCE(SUM(rf.gift_card_refunded), 0) AS total
+			FROM refunds rf
+			WHERE %s
+		)
+		SELECT sales.gross, sales.discount, sales.tax - returns.tax, sales.service_charge - returns.service_charge,
+			   sales.total - returns.total, returns.total, gift_card_returns.total, sales.transaksi
+		FROM sales, returns, gift_card_returns
+	`, models.TransactionStatusVoided, inRange("t.created_at"), inRefundRange("rf.created_at"), inRefundRange("rf.created_at"))
+
+	err := r.db.QueryRow(queryTotal, args...).Scan(&report.GrossRevenue, &report.TotalDiscount, &report.TotalTax,
+		&report.TotalServiceCharge, &report.TotalRevenue, &report.TotalRefund, &report.RefundGiftCard, &report.TotalTransaksi)
 	if err != nil {
 		return nil, err
 	}
+	report.RefundOther = report.TotalRefund - report.RefundGiftCard
 
-	// Query untuk produk terlaris hari ini
-	queryTop := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) as qty_terjual
-		FROM transaction_details td
-		JOIN transactions t ON td.transaction_id = t.id
-		JOIN products p ON td.product_id = p.id
-		WHERE DATE(t.created_at) = CURRENT_DATE
-		GROUP BY p.id, p.name
+	// Query untuk produk terlaris, qty terjual dikurangi qty yang di-refund.
+	// Nama produk diambil dari snapshot penjualan terakhir di periode itu
+	queryTop := fmt.Sprintf(`
+		SELECT (ARRAY_AGG(x.product_name ORDER BY x.detail_id DESC))[1], SUM(x.qty) as qty_terjual
+		FROM (
+			SELECT td.id AS detail_id, td.product_id, td.product_name, td.quantity AS qty
+			FROM transaction_details td
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE %s
+			UNION ALL
+			SELECT td.id, td.product_id, td.product_name, -rd.quantity AS qty
+			FROM refund_details rd
+			JOIN refunds rf ON rd.refund_id = rf.id
+			JOIN transaction_details td ON rd.transaction_detail_id = td.id
+			WHERE %s
+		) x
+		GROUP BY x.product_id
+		HAVING SUM(x.qty) > 0
 		ORDER BY qty_terjual DESC
 		LIMIT 1
-	`
+	`, inRange("t.created_at"), inRefundRange("rf.created_at"))
+
 	topProduct := &models.TopProduct{}
-	err = r.db.QueryRow(queryTop).Scan(&topProduct.Nama, &topProduct.QtyTerjual)
+	err = r.db.QueryRow(queryTop, args...).Scan(&topProduct.Nama, &topProduct.QtyTerjual)
 	if err == sql.ErrNoRows {
 		report.ProdukTerlaris = nil
 	} else if err != nil {
@@ -51,44 +113,163 @@ func (r *ReportRepository) GetDailySales() (*models.DailySalesReport, error) {
 		report.ProdukTerlaris = topProduct
 	}
 
+	report.PaymentMethods, err = r.getPaymentMethodTotals(inRange("t.created_at"), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ProfitPerProduct, report.ProfitPerParentProduct, report.ProfitPerCategory, err = r.getProfitLines(inRange("t.created_at"), inRefundRange("rf.created_at"), args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range report.ProfitPerProduct {
+		report.NetSales += line.NetSales
+		report.TotalCOGS += line.COGS
+	}
+	report.GrossProfit = report.NetSales - report.TotalCOGS
+	report.MarginPercent = marginPercent(report.GrossProfit, report.NetSales)
+
 	return report, nil
 }
 
-// GetReportByDateRange buat ambil laporan penjualan berdasarkan range tanggal
-func (r *ReportRepository) GetReportByDateRange(startDate, endDate string) (*models.DailySalesReport, error) {
-	report := &models.DailySalesReport{}
-
-	// Query untuk total revenue dan total transaksi dalam range
-	queryTotal := `
-		SELECT COALESCE(SUM(total_amount), 0), COUNT(*)
-		FROM transactions
-		WHERE DATE(created_at) >= $1 AND DATE(created_at) <= $2
-	`
-	err := r.db.QueryRow(queryTotal, startDate, endDate).Scan(&report.TotalRevenue, &report.TotalTransaksi)
+// getProfitLines buat ngitung penjualan bersih, HPP, dan laba kotor per produk (varian), per produk induk,
+// dan per kategori. Produk induk itu varian yang digabung ke induknya, produk tanpa varian jadi induknya sendiri.
+// Nama produk dan kategori diambil dari snapshot penjualan terakhir di periode itu.
+// Penjualan bersih per baris = total - pajak - service charge, HPP = unit_cost snapshot waktu checkout * qty.
+// Barang yang di-refund di periode yang sama dikurangi pakai unit_cost baris transaksi asalnya
+func (r *ReportRepository) getProfitLines(salesCondition, returnsCondition string, args ...interface{}) (products, parents, categories []models.ProfitLine, err error) {
+	query := fmt.Sprintf(`
+		SELECT x.product_id, (ARRAY_AGG(x.product_name ORDER BY x.detail_id DESC))[1],
+			   (ARRAY_AGG(x.parent_id ORDER BY x.detail_id DESC))[1], (ARRAY_AGG(x.parent_name ORDER BY x.detail_id DESC))[1],
+			   (ARRAY_AGG(x.category_id ORDER BY x.detail_id DESC))[1], (ARRAY_AGG(x.category_name ORDER BY x.detail_id DESC))[1],
+			   SUM(x.qty), SUM(x.net_sales), SUM(x.cogs)
+		FROM (
+			SELECT td.id AS detail_id, td.product_id, td.product_name,
+				   COALESCE(td.parent_product_id, 0) AS parent_id, td.parent_product_name AS parent_name,
+				   COALESCE(td.category_id, 0) AS category_id,
+				   td.category_name, td.quantity AS qty,
+				   td.total - td.tax_amount - td.service_charge_amount AS net_sales,
+				   td.unit_cost * td.quantity AS cogs
+			FROM transaction_details td
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE %s
+			UNION ALL
+			SELECT td.id, td.product_id, td.product_name, COALESCE(td.parent_product_id, 0), td.parent_product_name,
+				   COALESCE(td.category_id, 0), td.category_name, -rd.quantity,
+				   -(rd.amount - rd.tax_amount - rd.service_charge_amount),
+				   -(td.unit_cost * rd.quantity)
+			FROM refund_details rd
+			JOIN refunds rf ON rd.refund_id = rf.id
+			JOIN transaction_details td ON rd.transaction_detail_id = td.id
+			WHERE %s
+		) x
+		GROUP BY x.product_id
+		ORDER BY SUM(x.net_sales) - SUM(x.cogs) DESC, x.product_id
+	`, salesCondition, returnsCondition)
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
+	defer rows.Close()
 
-	// Query untuk produk terlaris dalam range
-	queryTop := `
-		SELECT p.name, COALESCE(SUM(td.quantity), 0) as qty_terjual
-		FROM transaction_details td
-		JOIN transactions t ON td.transaction_id = t.id
-		JOIN products p ON td.product_id = p.id
-		WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2
-		GROUP BY p.id, p.name
-		ORDER BY qty_terjual DESC
-		LIMIT 1
-	`
-	topProduct := &models.TopProduct{}
-	err = r.db.QueryRow(queryTop, startDate, endDate).Scan(&topProduct.Nama, &topProduct.QtyTerjual)
-	if err == sql.ErrNoRows {
-		report.ProdukTerlaris = nil
-	} else if err != nil {
+	products = make([]models.ProfitLine, 0)
+	parents = make([]models.ProfitLine, 0)
+	categories = make([]models.ProfitLine, 0)
+	parentIndex := make(map[int]int)
+	categoryIndex := make(map[int]int)
+	for rows.Next() {
+		var line models.ProfitLine
+		var productID sql.NullInt64
+		var parentID, categoryID int
+		var parentName, categoryName string
+		err := rows.Scan(&productID, &line.Name, &parentID, &parentName, &categoryID, &categoryName, &line.QtySold, &line.NetSales, &line.COGS)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		line.ID = int(productID.Int64)
+		line.GrossProfit = line.NetSales - line.COGS
+		line.MarginPercent = marginPercent(line.GrossProfit, line.NetSales)
+		products = append(products, line)
+
+		// Produk yang bukan varian jadi induknya sendiri
+		if parentID == 0 {
+			parentID, parentName = line.ID, line.Name
+		}
+		parents = addProfitLine(parents, parentIndex, parentID, parentName, line)
+
+		// Produk tanpa kategori dikumpulin di kategori ID 0
+		if categoryName == "" {
+			categoryName = "Tanpa Kategori"
+		}
+		categories = addProfitLine(categories, categoryIndex, categoryID, categoryName, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, lines := range [][]models.ProfitLine{parents, categories} {
+		for i := range lines {
+			lines[i].GrossProfit = lines[i].NetSales - lines[i].COGS
+			lines[i].MarginPercent = marginPercent(lines[i].GrossProfit, lines[i].NetSales)
+		}
+		sort.SliceStable(lines, func(i, j int) bool { return lines[i].GrossProfit > lines[j].GrossProfit })
+	}
+
+	return products, parents, categories, nil
+}
+
+// addProfitLine buat nambahin qty, penjualan bersih, dan HPP line ke grup id (induk atau kategori).
+// Laba dan margin grup dihitung setelah semua line masuk
+func addProfitLine(groups []models.ProfitLine, index map[int]int, id int, name string, line models.ProfitLine) []models.ProfitLine {
+	i, ok := index[id]
+	if !ok {
+		groups = append(groups, models.ProfitLine{ID: id, Name: name})
+		i = len(groups) - 1
+		index[id] = i
+	}
+	groups[i].QtySold += line.QtySold
+	groups[i].NetSales += line.NetSales
+	groups[i].COGS += line.COGS
+	return groups
+}
+
+// marginPercent buat ngitung margin laba kotor dalam persen, dibulatkan 2 angka di belakang koma
+func marginPercent(grossProfit, netSales int) float64 {
+	if netSales == 0 {
+		return 0
+	}
+	return math.Round(float64(grossProfit)*10000/float64(netSales)) / 100
+}
+
+// getPaymentMethodTotals buat ambil uang kotor yang diterima per metode pembayaran. Transaksi yang di-void
+// tetap dihitung uangnya karena void-nya udah tercatat sebagai refund, sama kayak di total revenue.
+// Jumlah transaksinya cuma yang nggak di-void
+func (r *ReportRepository) getPaymentMethodTotals(condition string, args ...interface{}) ([]models.PaymentMethodTotal, error) {
+	query := fmt.Sprintf(`
+		SELECT tp.method, COALESCE(SUM(tp.amount), 0), COUNT(DISTINCT tp.transaction_id) FILTER (WHERE t.status <> '%s')
+		FROM transaction_payments tp
+		JOIN transactions t ON tp.transaction_id = t.id
+		WHERE %s
+		GROUP BY tp.method
+		ORDER BY tp.method
+	`, models.TransactionStatusVoided, condition)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
 		return nil, err
-	} else {
-		report.ProdukTerlaris = topProduct
 	}
+	defer rows.Close()
 
-	return report, nil
+	totals := make([]models.PaymentMethodTotal, 0)
+	for rows.Next() {
+		var t models.PaymentMethodTotal
+		err := rows.Scan(&t.Method, &t.GrossCollected, &t.TotalTransaksi)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+
+	return totals, rows.Err()
 }
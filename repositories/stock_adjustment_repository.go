@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type StockAdjustmentRepository struct {
+	db *sql.DB
+}
+
+// NewStockAdjustmentRepository buat bikin instance repository baru
+func NewStockAdjustmentRepository(db *sql.DB) *StockAdjustmentRepository {
+	return &StockAdjustmentRepository{db: db}
+}
+
+// Create buat nyimpen stock adjustment, ubah stock produk, dan nyatet ledger-nya dalam satu DB transaction
+func (r *StockAdjustmentRepository) Create(adjustment *models.StockAdjustment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var productName string
+	var trackLots bool
+	err = tx.QueryRow("SELECT name, track_lots FROM products WHERE id = $1 FOR UPDATE", adjustment.ProductID).Scan(&productName, &trackLots)
+	if err == sql.ErrNoRows {
+		return errors.New("product not found")
+	}
+	if err != nil {
+		return err
+	}
+	if !trackLots && (adjustment.LotID != 0 || adjustment.BatchNumber != "" || adjustment.ExpiryDate != "") {
+		return fmt.Errorf("product %s does not track lots", productName)
+	}
+
+	var outletName string
+	adjustment.OutletID, outletName, err = resolveOutlet(tx, adjustment.OutletID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO stock_adjustments (product_id, outlet_id, quantity, reason, note, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		adjustment.ProductID, adjustment.OutletID, adjustment.Quantity, adjustment.Reason, adjustment.Note, adjustment.CreatedBy,
+	).Scan(&adjustment.ID, &adjustment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	note := adjustment.Reason
+	if adjustment.Note != "" {
+		note += ": " + adjustment.Note
+	}
+	movement := &models.StockMovement{
+		ProductID:     adjustment.ProductID,
+		OutletID:      adjustment.OutletID,
+		Quantity:      adjustment.Quantity,
+		Reason:        models.StockReasonAdjustment,
+		ReferenceType: models.StockReferenceAdjustment,
+		ReferenceID:   adjustment.ID,
+		Note:          note,
+		CreatedBy:     adjustment.CreatedBy,
+		LotID:         adjustment.LotID,
+		BatchNumber:   adjustment.BatchNumber,
+		ExpiryDate:    adjustment.ExpiryDate,
+	}
+	err = moveStock(tx, movement)
+	if err == ErrInsufficientStock && adjustment.LotID != 0 {
+		return fmt.Errorf("insufficient stock in lot id %d for product %s", adjustment.LotID, productName)
+	}
+	if err == ErrInsufficientStock {
+		return fmt.Errorf("insufficient stock for product %s at outlet %s", productName, outletName)
+	}
+	if err != nil {
+		return err
+	}
+	adjustment.StockAfter = movement.StockAfter
+	adjustment.OutletStockAfter = movement.OutletStockAfter
+
+	return tx.Commit()
+}
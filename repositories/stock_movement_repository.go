@@ -0,0 +1,463 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+// ErrInsufficientStock dikembalikan kalau perubahan stock bikin stock jadi minus
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ErrExpiredStock dikembalikan kalau stock yang belum kadaluarsa nggak cukup buat penjualan
+var ErrExpiredStock = errors.New("insufficient non-expired stock, set allow_expired to sell from expired lots")
+
+type StockMovementRepository struct {
+	db *sql.DB
+}
+
+// NewStockMovementRepository buat bikin instance repository baru
+func NewStockMovementRepository(db *sql.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// GetByProductID buat ambil ledger stock satu produk, terbaru duluan
+func (r *StockMovementRepository) GetByProductID(productID, page, limit int) ([]models.StockMovement, int, error) {
+	var total int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM stock_movements WHERE product_id = $1", productID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, product_id, COALESCE(outlet_id, 0), quantity, stock_after, COALESCE(outlet_stock_after, 0), reason,
+		 COALESCE(reference_type, ''), COALESCE(reference_id, 0), COALESCE(note, ''), COALESCE(created_by, ''), created_at
+		 FROM stock_movements
+		 WHERE product_id = $1
+		 ORDER BY id DESC
+		 LIMIT $2 OFFSET $3`,
+		productID, limit, (page-1)*limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	movements := make([]models.StockMovement, 0)
+	for rows.Next() {
+		var m models.StockMovement
+		err := rows.Scan(&m.ID, &m.ProductID, &m.OutletID, &m.Quantity, &m.StockAfter, &m.OutletStockAfter, &m.Reason,
+			&m.ReferenceType, &m.ReferenceID, &m.Note, &m.CreatedBy, &m.CreatedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		movements = append(movements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.loadMovementLots(movements); err != nil {
+		return nil, 0, err
+	}
+	return movements, total, nil
+}
+
+// loadMovementLots buat ngisi Lots baris-baris ledger sekaligus dalam satu query
+func (r *StockMovementRepository) loadMovementLots(movements []models.StockMovement) error {
+	ids := make([]int64, 0, len(movements))
+	index := make(map[int]int, len(movements))
+	for i := range movements {
+		ids = append(ids, int64(movements[i].ID))
+		index[movements[i].ID] = i
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT ml.stock_movement_id, ml.lot_id, l.batch_number, COALESCE(TO_CHAR(l.expiry_date, 'YYYY-MM-DD'), ''), ml.quantity
+		 FROM stock_movement_lots ml
+		 JOIN stock_lots l ON l.id = ml.lot_id
+		 WHERE ml.stock_movement_id = ANY($1)
+		 ORDER BY ml.id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movementID int
+		var l models.StockMovementLot
+		if err := rows.Scan(&movementID, &l.LotID, &l.BatchNumber, &l.ExpiryDate, &l.Quantity); err != nil {
+			return err
+		}
+		m := &movements[index[movementID]]
+		m.Lots = append(m.Lots, l)
+	}
+	return rows.Err()
+}
+
+// GetLots buat ambil lot produk yang masih ada stock-nya, urut FEFO. outletID 0 berarti semua outlet
+func (r *StockMovementRepository) GetLots(productID, outletID int) ([]models.StockLot, error) {
+	var trackLots bool
+	err := r.db.QueryRow("SELECT track_lots FROM products WHERE id = $1", productID).Scan(&trackLots)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("product not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !trackLots {
+		return nil, errors.New("product does not track lots")
+	}
+
+	rows, err := r.db.Query(
+		`SELECT l.id, l.product_id, l.outlet_id, o.name, l.batch_number, COALESCE(TO_CHAR(l.expiry_date, 'YYYY-MM-DD'), ''),
+		 l.quantity, COALESCE(l.expiry_date < CURRENT_DATE, FALSE), l.created_at
+		 FROM stock_lots l
+		 JOIN outlets o ON o.id = l.outlet_id
+		 WHERE l.product_id = $1 AND l.quantity > 0 AND ($2 = 0 OR l.outlet_id = $2)
+		 ORDER BY l.expiry_date NULLS LAST, l.id`,
+		productID, outletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := make([]models.StockLot, 0)
+	for rows.Next() {
+		var l models.StockLot
+		err := rows.Scan(&l.ID, &l.ProductID, &l.OutletID, &l.OutletName, &l.BatchNumber, &l.ExpiryDate, &l.Quantity, &l.Expired, &l.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// GetExpiring buat ambil lot yang masih ada stock-nya dan kadaluarsa dalam withinDays hari ke depan,
+// termasuk yang udah lewat kadaluarsa. Paling cepat kadaluarsa duluan. outletID 0 berarti semua outlet
+func (r *StockMovementRepository) GetExpiring(withinDays, outletID int) ([]models.ExpiringLot, error) {
+	rows, err := r.db.Query(
+		`SELECT l.id, l.product_id, p.name, COALESCE(p.sku, ''), l.outlet_id, o.name, l.batch_number,
+		 TO_CHAR(l.expiry_date, 'YYYY-MM-DD'), l.quantity, l.expiry_date - CURRENT_DATE
+		 FROM stock_lots l
+		 JOIN products p ON p.id = l.product_id
+		 JOIN outlets o ON o.id = l.outlet_id
+		 WHERE l.quantity > 0 AND l.expiry_date <= CURRENT_DATE + $1::int AND ($2 = 0 OR l.outlet_id = $2)
+		 ORDER BY l.expiry_date, l.id`,
+		withinDays, outletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lots := make([]models.ExpiringLot, 0)
+	for rows.Next() {
+		var l models.ExpiringLot
+		err := rows.Scan(&l.LotID, &l.ProductID, &l.ProductName, &l.SKU, &l.OutletID, &l.OutletName, &l.BatchNumber, &l.ExpiryDate,
+			&l.Quantity, &l.DaysLeft)
+		if err != nil {
+			return nil, err
+		}
+		l.Expired = l.DaysLeft < 0
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// GetBalance buat ambil stock produk sekarang dan stock hasil jumlah ledger-nya, buat rekonsiliasi
+func (r *StockMovementRepository) GetBalance(productID int) (stock int, ledgerStock int, err error) {
+	err = r.db.QueryRow(
+		`SELECT p.stock, COALESCE((SELECT SUM(m.quantity) FROM stock_movements m WHERE m.product_id = p.id), 0)
+		 FROM products p WHERE p.id = $1`,
+		productID,
+	).Scan(&stock, &ledgerStock)
+	if err == sql.ErrNoRows {
+		return 0, 0, errors.New("product not found")
+	}
+	return stock, ledgerStock, err
+}
+
+// moveStock buat ubah stock produk sekaligus nyatet ledger-nya dalam DB transaction yang sama.
+// Semua perubahan stock wajib lewat sini biar ledger selalu cocok sama products.stock.
+// Balikin ErrInsufficientStock kalau stock jadi minus, dan ErrProductHasVariants kalau produknya
+// induk yang punya varian (stock induk selalu 0, yang dicatat stock tiap varian), atau ErrBundleStock
+// kalau produknya paket (stock paket ngikut komponennya). Produk track_lots sekalian ngubah lot-nya,
+// balikin ErrExpiredStock kalau SkipExpired dan stock yang belum kadaluarsa nggak cukup.
+// Stock outlet m.OutletID (0 = outlet default) ikut berubah, ErrInsufficientStock juga kalau stock di outlet itu nggak cukup.
+// OutletID selain 0 harus udah dicek pemanggilnya lewat resolveOutlet, kecuali lokasi transit dari transfer stock.
+// Kalau stock turun melewati min_stock, alert-nya dicatat di outbox low_stock_alerts sekalian
+func moveStock(tx *sql.Tx, m *models.StockMovement) error {
+	var hasVariants, isBundle, trackLots bool
+	var minStock int
+	err := tx.QueryRow(
+		`UPDATE products SET stock = stock + $1 WHERE id = $2 AND stock + $1 >= 0
+		 RETURNING stock, EXISTS (SELECT 1 FROM products v WHERE v.parent_id = products.id), is_bundle, track_lots, min_stock`,
+		m.Quantity, m.ProductID,
+	).Scan(&m.StockAfter, &hasVariants, &isBundle, &trackLots, &minStock)
+	if err == sql.ErrNoRows {
+		return ErrInsufficientStock
+	}
+	if err != nil {
+		return err
+	}
+	if hasVariants {
+		return ErrProductHasVariants
+	}
+	if isBundle {
+		return ErrBundleStock
+	}
+
+	if m.OutletID == 0 {
+		if m.OutletID, _, err = resolveOutlet(tx, 0); err != nil {
+			return err
+		}
+	}
+	// Row produk udah ke-lock sama UPDATE di atas, jadi insert stock outlet yang belum ada nggak bakal balapan
+	err = tx.QueryRow(
+		`UPDATE outlet_stocks SET stock = stock + $1 WHERE outlet_id = $2 AND product_id = $3 AND stock + $1 >= 0 RETURNING stock`,
+		m.Quantity, m.OutletID, m.ProductID,
+	).Scan(&m.OutletStockAfter)
+	if err == sql.ErrNoRows && m.Quantity >= 0 {
+		err = tx.QueryRow(
+			"INSERT INTO outlet_stocks (outlet_id, product_id, stock) VALUES ($1, $2, $3) RETURNING stock",
+			m.OutletID, m.ProductID, m.Quantity,
+		).Scan(&m.OutletStockAfter)
+	}
+	if err == sql.ErrNoRows {
+		return ErrInsufficientStock
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := insertStockMovement(tx, m); err != nil {
+		return err
+	}
+	// Produk yang udah low dan berkurang lagi nggak dicatat biar notifikasinya nggak berulang tiap penjualan.
+	// transfer_out selalu dipasangkan sama transfer_in di DB transaction yang sama (outlet asal ke lokasi transit,
+	// lokasi transit ke outlet tujuan), stock totalnya nggak berubah jadi nggak dicatat juga
+	if m.Quantity < 0 && m.Reason != models.StockReasonTransferOut && minStock > 0 && m.StockAfter <= minStock &&
+		m.StockAfter-m.Quantity > minStock {
+		if _, err := tx.Exec("INSERT INTO low_stock_alerts (stock_movement_id) VALUES ($1)", m.ID); err != nil {
+			return err
+		}
+	}
+	if !trackLots {
+		return nil
+	}
+	return moveLots(tx, m)
+}
+
+// moveLots buat ngubah lot produk track_lots sesuai satu baris ledger yang udah di-insert
+func moveLots(tx *sql.Tx, m *models.StockMovement) error {
+	m.Lots = nil
+	if m.Quantity > 0 {
+		lotID := m.LotID
+		if lotID == 0 {
+			var err error
+			lotID, err = findOrCreateLot(tx, m.ProductID, m.OutletID, m.BatchNumber, m.ExpiryDate)
+			if err != nil {
+				return err
+			}
+		}
+		return changeLot(tx, m, lotID, m.Quantity)
+	}
+
+	need := -m.Quantity
+	if m.LotID != 0 {
+		return changeLot(tx, m, m.LotID, -need)
+	}
+
+	// FEFO: lot outlet ini yang paling cepat kadaluarsa diambil duluan, lot tanpa kadaluarsa paling akhir
+	query := `SELECT id, quantity FROM stock_lots
+			  WHERE product_id = $1 AND outlet_id = $2 AND quantity > 0`
+	if m.SkipExpired {
+		query += ` AND (expiry_date IS NULL OR expiry_date >= CURRENT_DATE)`
+	}
+	query += ` ORDER BY expiry_date NULLS LAST, id FOR UPDATE`
+	rows, err := tx.Query(query, m.ProductID, m.OutletID)
+	if err != nil {
+		return err
+	}
+	type lotTake struct{ id, quantity int }
+	takes := make([]lotTake, 0)
+	for rows.Next() && need > 0 {
+		var id, available int
+		if err := rows.Scan(&id, &available); err != nil {
+			rows.Close()
+			return err
+		}
+		take := min(available, need)
+		takes = append(takes, lotTake{id, take})
+		need -= take
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if need > 0 {
+		if m.SkipExpired {
+			return ErrExpiredStock
+		}
+		return ErrInsufficientStock
+	}
+
+	for _, t := range takes {
+		if err := changeLot(tx, m, t.id, -t.quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findOrCreateLot buat nyari lot produk di satu outlet dengan batch dan tanggal kadaluarsa yang sama, dibikin kalau belum ada
+func findOrCreateLot(tx *sql.Tx, productID, outletID int, batchNumber, expiryDate string) (int, error) {
+	var id int
+	err := tx.QueryRow(
+		`SELECT id FROM stock_lots
+		 WHERE product_id = $1 AND outlet_id = $2 AND batch_number = $3 AND expiry_date IS NOT DISTINCT FROM NULLIF($4, '')::date
+		 ORDER BY id LIMIT 1`,
+		productID, outletID, batchNumber, expiryDate,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(
+			`INSERT INTO stock_lots (product_id, outlet_id, batch_number, expiry_date)
+			 VALUES ($1, $2, $3, NULLIF($4, '')::date) RETURNING id`,
+			productID, outletID, batchNumber, expiryDate,
+		).Scan(&id)
+	}
+	return id, err
+}
+
+// changeLot buat nambah/ngurangin quantity satu lot dan nyatet perubahannya di ledger lot
+func changeLot(tx *sql.Tx, m *models.StockMovement, lotID, quantity int) error {
+	l := models.StockMovementLot{LotID: lotID, Quantity: quantity}
+	err := tx.QueryRow(
+		`UPDATE stock_lots SET quantity = quantity + $1
+		 WHERE id = $2 AND product_id = $3 AND outlet_id = $4 AND quantity + $1 >= 0
+		 RETURNING batch_number, COALESCE(TO_CHAR(expiry_date, 'YYYY-MM-DD'), '')`,
+		quantity, lotID, m.ProductID, m.OutletID,
+	).Scan(&l.BatchNumber, &l.ExpiryDate)
+	if err == sql.ErrNoRows {
+		var exists bool
+		err := tx.QueryRow(
+			"SELECT EXISTS (SELECT 1 FROM stock_lots WHERE id = $1 AND product_id = $2 AND outlet_id = $3)",
+			lotID, m.ProductID, m.OutletID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("lot id %d not found for this product at this outlet", lotID)
+		}
+		return ErrInsufficientStock
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO stock_movement_lots (stock_movement_id, lot_id, quantity) VALUES ($1, $2, $3)",
+		m.ID, lotID, quantity,
+	)
+	if err != nil {
+		return err
+	}
+	m.Lots = append(m.Lots, l)
+	return nil
+}
+
+// insertStockMovement buat nyatet satu baris ledger, OutletID, StockAfter dan OutletStockAfter harus udah diisi
+func insertStockMovement(tx *sql.Tx, m *models.StockMovement) error {
+	return tx.QueryRow(
+		`INSERT INTO stock_movements (product_id, outlet_id, quantity, stock_after, outlet_stock_after, reason, reference_type,
+		 reference_id, note, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, NULLIF($9, ''), NULLIF($10, '')) RETURNING id, created_at`,
+		m.ProductID, m.OutletID, m.Quantity, m.StockAfter, m.OutletStockAfter, m.Reason, m.ReferenceType, nullInt(m.ReferenceID),
+		m.Note, m.CreatedBy,
+	).Scan(&m.ID, &m.CreatedAt)
+}
+
+// GetLowStock buat ambil produk yang stock-nya udah di bawah atau sama dengan min_stock.
+// Produk dengan min_stock 0 nggak dipantau
+func (r *StockMovementRepository) GetLowStock() ([]models.LowStockProduct, error) {
+	rows, err := r.db.Query(
+		`SELECT id, name, COALESCE(sku, ''), stock, min_stock, reorder_quantity
+		 FROM products
+		 WHERE min_stock > 0 AND stock <= min_stock
+		 ORDER BY stock - min_stock, id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]models.LowStockProduct, 0)
+	for rows.Next() {
+		var p models.LowStockProduct
+		err := rows.Scan(&p.ProductID, &p.ProductName, &p.SKU, &p.Stock, &p.MinStock, &p.ReorderQuantity)
+		if err != nil {
+			return nil, err
+		}
+		p.SuggestedOrderQuantity = suggestedOrderQuantity(p)
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
+
+// GetPendingLowStockAlerts buat ambil alert stock menipis dari outbox yang belum terkirim, urut kejadian.
+// Outbox-nya diisi moveStock di DB transaction yang sama dengan pergerakan stocknya, jadi pergerakan
+// yang commit belakangan tetap kebaca walaupun ID-nya lebih kecil
+func (r *StockMovementRepository) GetPendingLowStockAlerts() ([]models.LowStockAlert, error) {
+	rows, err := r.db.Query(
+		`SELECT m.id, m.product_id, p.name, COALESCE(p.sku, ''), m.stock_after, p.min_stock, p.reorder_quantity,
+		 m.reason, COALESCE(m.reference_type, ''), COALESCE(m.reference_id, 0), m.created_at
+		 FROM low_stock_alerts a
+		 JOIN stock_movements m ON m.id = a.stock_movement_id
+		 JOIN products p ON p.id = m.product_id
+		 WHERE a.notified_at IS NULL
+		 ORDER BY a.stock_movement_id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := make([]models.LowStockAlert, 0)
+	for rows.Next() {
+		var a models.LowStockAlert
+		err := rows.Scan(&a.MovementID, &a.ProductID, &a.ProductName, &a.SKU, &a.Stock, &a.MinStock, &a.ReorderQuantity,
+			&a.Reason, &a.ReferenceType, &a.ReferenceID, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		a.SuggestedOrderQuantity = suggestedOrderQuantity(a.LowStockProduct)
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
+
+// MarkLowStockAlertNotified buat nandain alert di outbox udah terkirim biar nggak dikirim ulang
+func (r *StockMovementRepository) MarkLowStockAlertNotified(movementID int) error {
+	_, err := r.db.Exec("UPDATE low_stock_alerts SET notified_at = NOW() WHERE stock_movement_id = $1", movementID)
+	return err
+}
+
+// suggestedOrderQuantity buat ngitung saran jumlah pesan ulang: reorder_quantity, atau
+// kekurangan sampai min_stock kalau itu lebih besar
+func suggestedOrderQuantity(p models.LowStockProduct) int {
+	return max(p.ReorderQuantity, p.MinStock-p.Stock)
+}
@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type StockOpnameRepository struct {
+	db *sql.DB
+}
+
+// NewStockOpnameRepository buat bikin instance repository baru
+func NewStockOpnameRepository(db *sql.DB) *StockOpnameRepository {
+	return &StockOpnameRepository{db: db}
+}
+
+const stockOpnameColumns = `so.id, so.name, COALESCE(so.outlet_id, 0), COALESCE(o.name, ''), so.status, so.created_by,
+	COALESCE(so.finalized_by, ''), so.created_at, so.finalized_at`
+
+const stockOpnameFrom = ` FROM stock_opnames so LEFT JOIN outlets o ON o.id = so.outlet_id`
+
+// scanStockOpname buat scan satu row stock_opnames sesuai urutan stockOpnameColumns
+func scanStockOpname(row interface{ Scan(...interface{}) error }) (*models.StockOpname, error) {
+	var o models.StockOpname
+	var finalizedAt sql.NullTime
+	err := row.Scan(&o.ID, &o.Name, &o.OutletID, &o.OutletName, &o.Status, &o.CreatedBy, &o.FinalizedBy, &o.CreatedAt, &finalizedAt)
+	if err != nil {
+		return nil, err
+	}
+	if finalizedAt.Valid {
+		o.FinalizedAt = &finalizedAt.Time
+	}
+	return &o, nil
+}
+
+// GetAll buat ambil semua sesi stock opname, terbaru duluan
+func (r *StockOpnameRepository) GetAll() ([]models.StockOpname, error) {
+	rows, err := r.db.Query("SELECT " + stockOpnameColumns + stockOpnameFrom + " ORDER BY so.id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	opnames := make([]models.StockOpname, 0)
+	for rows.Next() {
+		o, err := scanStockOpname(rows)
+		if err != nil {
+			return nil, err
+		}
+		opnames = append(opnames, *o)
+	}
+
+	return opnames, rows.Err()
+}
+
+// GetByID buat ambil sesi stock opname beserta hasil hitungnya
+func (r *StockOpnameRepository) GetByID(id int) (*models.StockOpname, error) {
+	o, err := scanStockOpname(r.db.QueryRow("SELECT "+stockOpnameColumns+stockOpnameFrom+" WHERE so.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("stock opname not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT i.id, i.stock_opname_id, i.product_id, p.name, COALESCE(i.system_stock, os.stock, 0), i.counted_quantity, i.counted_by, i.counted_at
+		 FROM stock_opname_items i
+		 JOIN products p ON p.id = i.product_id
+		 LEFT JOIN outlet_stocks os ON os.product_id = i.product_id AND os.outlet_id = $2
+		 WHERE i.stock_opname_id = $1
+		 ORDER BY i.product_id`,
+		id, o.OutletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	o.Items = make([]models.StockOpnameItem, 0)
+	for rows.Next() {
+		var i models.StockOpnameItem
+		err := rows.Scan(&i.ID, &i.StockOpnameID, &i.ProductID, &i.ProductName, &i.SystemStock, &i.CountedQuantity, &i.CountedBy, &i.CountedAt)
+		if err != nil {
+			return nil, err
+		}
+		o.Items = append(o.Items, i)
+	}
+
+	return o, rows.Err()
+}
+
+// Create buat buka sesi stock opname baru di satu outlet
+func (r *StockOpnameRepository) Create(opname *models.StockOpname) error {
+	var err error
+	opname.OutletID, opname.OutletName, err = resolveOutlet(r.db, opname.OutletID)
+	if err != nil {
+		return err
+	}
+
+	opname.Status = models.StockOpnameStatusOpen
+	return r.db.QueryRow(
+		"INSERT INTO stock_opnames (name, outlet_id, status, created_by) VALUES ($1, $2, $3, $4) RETURNING id, created_at",
+		opname.Name, opname.OutletID, opname.Status, opname.CreatedBy,
+	).Scan(&opname.ID, &opname.CreatedAt)
+}
+
+// lockOpenStockOpname buat lock row sesi dan mastiin statusnya masih open.
+// Submit hitungan pakai FOR SHARE biar beberapa device bisa barengan, finalize pakai FOR UPDATE
+func lockOpenStockOpname(tx *sql.Tx, id int, lock string) (*models.StockOpname, error) {
+	o, err := scanStockOpname(tx.QueryRow("SELECT "+stockOpnameColumns+stockOpnameFrom+" WHERE so.id = $1 "+lock+" OF so", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("stock opname not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != models.StockOpnameStatusOpen {
+		return nil, errors.New("stock opname is already finalized")
+	}
+	return o, nil
+}
+
+// SubmitCounts buat nyimpen hitungan fisik, hitungan produk yang udah ada diganti yang terbaru.
+// Stock outlet waktu itu disimpan sebagai system_stock, selisihnya dihitung dari situ biar penjualan
+// antara dihitung dan finalize nggak kecatat jadi selisih
+func (r *StockOpnameRepository) SubmitCounts(id int, req models.StockOpnameCountRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	opname, err := lockOpenStockOpname(tx, id, "FOR SHARE")
+	if err != nil {
+		return err
+	}
+
+	// Produk di-lock FOR SHARE urut ID kayak di checkout, jadi checkout yang lagi jalan ditunggu dulu
+	// dan stock yang dicatat udah termasuk penjualannya
+	items := append([]models.StockOpnameCount(nil), req.Items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ProductID < items[j].ProductID })
+
+	for _, item := range items {
+		var systemStock int
+		err := tx.QueryRow(
+			`SELECT COALESCE(os.stock, 0) FROM products p
+			 LEFT JOIN outlet_stocks os ON os.product_id = p.id AND os.outlet_id = $2
+			 WHERE p.id = $1
+			 FOR SHARE OF p`,
+			item.ProductID, opname.OutletID,
+		).Scan(&systemStock)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO stock_opname_items (stock_opname_id, product_id, counted_quantity, counted_by, system_stock)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (stock_opname_id, product_id)
+			 DO UPDATE SET counted_quantity = EXCLUDED.counted_quantity, counted_by = EXCLUDED.counted_by, counted_at = NOW(),
+			 system_stock = EXCLUDED.system_stock`,
+			id, item.ProductID, item.CountedQuantity, req.CountedBy, systemStock,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetVariances buat ambil selisih per produk dari stock sistem waktu dihitung. Sesi open dinilai pakai harga sekarang,
+// sesi finalized pakai harga yang disimpan waktu finalize
+func (r *StockOpnameRepository) GetVariances(id int) (string, []models.StockOpnameVariance, error) {
+	var status string
+	var outletID int
+	err := r.db.QueryRow("SELECT status, COALESCE(outlet_id, 0) FROM stock_opnames WHERE id = $1", id).Scan(&status, &outletID)
+	if err == sql.ErrNoRows {
+		return "", nil, errors.New("stock opname not found")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT i.product_id, p.name, COALESCE(i.system_stock, os.stock, 0), i.counted_quantity, COALESCE(i.unit_price, p.price)
+		 FROM stock_opname_items i
+		 JOIN products p ON p.id = i.product_id
+		 LEFT JOIN outlet_stocks os ON os.product_id = i.product_id AND os.outlet_id = $2
+		 WHERE i.stock_opname_id = $1
+		 ORDER BY i.product_id`,
+		id, outletID,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rows.Close()
+
+	variances := make([]models.StockOpnameVariance, 0)
+	for rows.Next() {
+		var v models.StockOpnameVariance
+		err := rows.Scan(&v.ProductID, &v.ProductName, &v.SystemStock, &v.CountedQuantity, &v.UnitPrice)
+		if err != nil {
+			return "", nil, err
+		}
+		v.Variance = v.CountedQuantity - v.SystemStock
+		v.VarianceValue = v.Variance * v.UnitPrice
+		variances = append(variances, v)
+	}
+
+	return status, variances, rows.Err()
+}
+
+// Finalize buat nutup sesi stock opname. Selisih hitungan vs stock sistem waktu dihitung di-posting sebagai
+// perubahan ke stock sekarang dan ledger dalam satu DB transaction, harga waktu itu disimpan buat laporan
+func (r *StockOpnameRepository) Finalize(id int, finalizedBy string) (*models.StockOpname, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	opname, err := lockOpenStockOpname(tx, id, "FOR UPDATE")
+	if err != nil {
+		return nil, err
+	}
+
+	// Produk di-lock urut ID kayak di checkout biar nggak deadlock. Hitungan lama yang belum punya
+	// system_stock pakai stock outlet sekarang
+	rows, err := tx.Query(
+		`SELECT i.id, i.product_id, i.counted_quantity, COALESCE(i.system_stock, os.stock, 0), p.price
+		 FROM stock_opname_items i
+		 JOIN products p ON p.id = i.product_id
+		 LEFT JOIN outlet_stocks os ON os.product_id = p.id AND os.outlet_id = $2
+		 WHERE i.stock_opname_id = $1
+		 ORDER BY p.id
+		 FOR UPDATE OF p`,
+		id, opname.OutletID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	type countedItem struct {
+		id, productID, counted, systemStock, price int
+	}
+	items := make([]countedItem, 0)
+	for rows.Next() {
+		var c countedItem
+		if err := rows.Scan(&c.id, &c.productID, &c.counted, &c.systemStock, &c.price); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("stock opname has no counted items")
+	}
+
+	for _, c := range items {
+		_, err = tx.Exec("UPDATE stock_opname_items SET system_stock = $1, unit_price = $2 WHERE id = $3", c.systemStock, c.price, c.id)
+		if err != nil {
+			return nil, err
+		}
+
+		variance := c.counted - c.systemStock
+		if variance == 0 {
+			continue
+		}
+		err = moveStock(tx, &models.StockMovement{
+			ProductID:     c.productID,
+			OutletID:      opname.OutletID,
+			Quantity:      variance,
+			Reason:        models.StockReasonOpname,
+			ReferenceType: models.StockReferenceOpname,
+			ReferenceID:   id,
+			Note:          opname.Name,
+			CreatedBy:     finalizedBy,
+		})
+		if err == ErrInsufficientStock {
+			return nil, fmt.Errorf("product id %d: variance %d would make stock negative, recount the product", c.productID, variance)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRow(
+		"UPDATE stock_opnames SET status = $1, finalized_by = $2, finalized_at = NOW() WHERE id = $3 RETURNING finalized_at",
+		models.StockOpnameStatusFinalized, finalizedBy, id,
+	).Scan(&opname.FinalizedAt)
+	if err != nil {
+		return nil, err
+	}
+	opname.Status = models.StockOpnameStatusFinalized
+	opname.FinalizedBy = finalizedBy
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return opname, nil
+}
@@ -0,0 +1,476 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+type StockTransferRepository struct {
+	db *sql.DB
+}
+
+// NewStockTransferRepository buat bikin instance repository baru
+func NewStockTransferRepository(db *sql.DB) *StockTransferRepository {
+	return &StockTransferRepository{db: db}
+}
+
+const stockTransferColumns = `st.id, st.from_outlet_id, fo.name, st.to_outlet_id, tout.name, st.status, st.note, st.requested_by,
+	COALESCE(st.shipped_by, ''), COALESCE(st.received_by, ''), st.created_at, st.shipped_at, st.received_at`
+
+const stockTransferFrom = ` FROM stock_transfers st
+	JOIN outlets fo ON fo.id = st.from_outlet_id
+	JOIN outlets tout ON tout.id = st.to_outlet_id`
+
+// scanStockTransfer buat scan satu baris stockTransferColumns, bisa dari *sql.Row atau *sql.Rows
+func scanStockTransfer(row interface {
+	Scan(dest ...interface{}) error
+}, t *models.StockTransfer) error {
+	return row.Scan(&t.ID, &t.FromOutletID, &t.FromOutletName, &t.ToOutletID, &t.ToOutletName, &t.Status, &t.Note, &t.RequestedBy,
+		&t.ShippedBy, &t.ReceivedBy, &t.CreatedAt, &t.ShippedAt, &t.ReceivedAt)
+}
+
+// GetAll buat ambil semua transfer stock beserta item-nya, bisa difilter status
+func (r *StockTransferRepository) GetAll(status string) ([]models.StockTransfer, error) {
+	query := `SELECT ` + stockTransferColumns + stockTransferFrom
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE st.status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY st.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]models.StockTransfer, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var t models.StockTransfer
+		if err := scanStockTransfer(rows, &t); err != nil {
+			return nil, err
+		}
+		t.Items = make([]models.StockTransferItem, 0)
+		transfers = append(transfers, t)
+		ids = append(ids, int64(t.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transfers {
+		transfers[i].Items = append(transfers[i].Items, items[transfers[i].ID]...)
+	}
+
+	return transfers, nil
+}
+
+// GetByID buat ambil transfer stock beserta item dan lot yang dikirim
+func (r *StockTransferRepository) GetByID(id int) (*models.StockTransfer, error) {
+	var t models.StockTransfer
+	err := scanStockTransfer(r.db.QueryRow(`SELECT `+stockTransferColumns+stockTransferFrom+` WHERE st.id = $1`, id), &t)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("stock transfer not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.getItems([]int64{int64(id)})
+	if err != nil {
+		return nil, err
+	}
+	t.Items = append(make([]models.StockTransferItem, 0), items[id]...)
+
+	return &t, nil
+}
+
+// getItems buat ambil item beberapa transfer sekaligus beserta lot-nya, dikelompokkan per stock_transfer_id
+func (r *StockTransferRepository) getItems(transferIDs []int64) (map[int][]models.StockTransferItem, error) {
+	result := make(map[int][]models.StockTransferItem)
+	if len(transferIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(
+		`SELECT i.id, i.stock_transfer_id, i.product_id, p.name, i.quantity
+		 FROM stock_transfer_items i
+		 JOIN products p ON p.id = i.product_id
+		 WHERE i.stock_transfer_id = ANY($1)
+		 ORDER BY i.id`,
+		pq.Array(transferIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.StockTransferItem, 0)
+	itemIDs := make([]int64, 0)
+	for rows.Next() {
+		var i models.StockTransferItem
+		if err := rows.Scan(&i.ID, &i.StockTransferID, &i.ProductID, &i.ProductName, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+		itemIDs = append(itemIDs, int64(i.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lots, err := getStockTransferLots(r.db, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range items {
+		i.Lots = lots[i.ID]
+		result[i.StockTransferID] = append(result[i.StockTransferID], i)
+	}
+
+	return result, nil
+}
+
+// getStockTransferLots buat ambil lot yang dikirim per stock_transfer_item_id. Bisa dipanggil pakai *sql.DB atau *sql.Tx
+func getStockTransferLots(q interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}, itemIDs []int64) (map[int][]models.StockTransferLot, error) {
+	result := make(map[int][]models.StockTransferLot)
+	if len(itemIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := q.Query(
+		`SELECT stock_transfer_item_id, batch_number, COALESCE(TO_CHAR(expiry_date, 'YYYY-MM-DD'), ''), quantity
+		 FROM stock_transfer_item_lots
+		 WHERE stock_transfer_item_id = ANY($1)
+		 ORDER BY id`,
+		pq.Array(itemIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID int
+		var l models.StockTransferLot
+		if err := rows.Scan(&itemID, &l.BatchNumber, &l.ExpiryDate, &l.Quantity); err != nil {
+			return nil, err
+		}
+		result[itemID] = append(result[itemID], l)
+	}
+
+	return result, rows.Err()
+}
+
+// Create buat bikin transfer stock baru dengan status requested. Outlet asal dan tujuan harus ada
+func (r *StockTransferRepository) Create(t *models.StockTransfer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if t.FromOutletID, t.FromOutletName, err = resolveOutlet(tx, t.FromOutletID); err != nil {
+		return fmt.Errorf("from_outlet_id: %w", err)
+	}
+	if t.ToOutletID, t.ToOutletName, err = resolveOutlet(tx, t.ToOutletID); err != nil {
+		return fmt.Errorf("to_outlet_id: %w", err)
+	}
+
+	t.Status = models.StockTransferStatusRequested
+	err = tx.QueryRow(
+		`INSERT INTO stock_transfers (from_outlet_id, to_outlet_id, status, note, requested_by)
+		 VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		t.FromOutletID, t.ToOutletID, t.Status, t.Note, t.RequestedBy,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range t.Items {
+		item := &t.Items[i]
+		item.StockTransferID = t.ID
+		item.Lots = nil
+		err := tx.QueryRow("SELECT name FROM products WHERE id = $1", item.ProductID).Scan(&item.ProductName)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(
+			"INSERT INTO stock_transfer_items (stock_transfer_id, product_id, quantity) VALUES ($1, $2, $3) RETURNING id",
+			t.ID, item.ProductID, item.Quantity,
+		).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// lockStockTransfer buat lock row transfer dan balikin status, outlet asal dan outlet tujuan
+func lockStockTransfer(tx *sql.Tx, id int) (status string, fromOutletID, toOutletID int, err error) {
+	err = tx.QueryRow(
+		"SELECT status, from_outlet_id, to_outlet_id FROM stock_transfers WHERE id = $1 FOR UPDATE", id,
+	).Scan(&status, &fromOutletID, &toOutletID)
+	if err == sql.ErrNoRows {
+		return "", 0, 0, errors.New("stock transfer not found")
+	}
+	return status, fromOutletID, toOutletID, err
+}
+
+// getLockedStockTransferItems buat ambil item transfer yang row-nya udah di-lock, urut product ID
+// biar urutan lock produk sama kayak checkout dan nggak deadlock
+func getLockedStockTransferItems(tx *sql.Tx, id int) ([]models.StockTransferItem, error) {
+	rows, err := tx.Query(
+		`SELECT i.id, i.product_id, p.name, i.quantity
+		 FROM stock_transfer_items i
+		 JOIN products p ON p.id = i.product_id
+		 WHERE i.stock_transfer_id = $1
+		 ORDER BY i.product_id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.StockTransferItem, 0)
+	for rows.Next() {
+		i := models.StockTransferItem{StockTransferID: id}
+		if err := rows.Scan(&i.ID, &i.ProductID, &i.ProductName, &i.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+// Ship buat ngirim transfer. Stock outlet asal berkurang (transfer_out) dan pindah ke lokasi transit (transfer_in)
+// sampai diterima, jadi stock total produknya nggak berubah selama barangnya di perjalanan. Buat produk track_lots
+// lot-nya diambil FEFO dan dicatat biar diterima ke batch yang sama
+func (r *StockTransferRepository) Ship(id int, by string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, fromOutletID, toOutletID, err := lockStockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.StockTransferStatusRequested {
+		return fmt.Errorf("cannot ship stock transfer with status %s", status)
+	}
+	_, fromName, err := resolveOutlet(tx, fromOutletID)
+	if err != nil {
+		return err
+	}
+	_, toName, err := resolveOutlet(tx, toOutletID)
+	if err != nil {
+		return err
+	}
+	transitID, err := transitOutlet(tx)
+	if err != nil {
+		return err
+	}
+
+	items, err := getLockedStockTransferItems(tx, id)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		m := &models.StockMovement{
+			ProductID:     item.ProductID,
+			OutletID:      fromOutletID,
+			Quantity:      -item.Quantity,
+			Reason:        models.StockReasonTransferOut,
+			ReferenceType: models.StockReferenceTransfer,
+			ReferenceID:   id,
+			Note:          "transfer ke " + toName,
+			CreatedBy:     by,
+		}
+		err := moveStock(tx, m)
+		if err == ErrInsufficientStock {
+			return fmt.Errorf("insufficient stock for product %s at outlet %s", item.ProductName, fromName)
+		}
+		if err != nil {
+			return fmt.Errorf("product %s: %w", item.ProductName, err)
+		}
+
+		shipped := make([]models.StockTransferLot, 0, len(m.Lots))
+		for _, l := range m.Lots {
+			_, err := tx.Exec(
+				`INSERT INTO stock_transfer_item_lots (stock_transfer_item_id, batch_number, expiry_date, quantity)
+				 VALUES ($1, $2, NULLIF($3, '')::date, $4)`,
+				item.ID, l.BatchNumber, l.ExpiryDate, -l.Quantity,
+			)
+			if err != nil {
+				return err
+			}
+			shipped = append(shipped, models.StockTransferLot{BatchNumber: l.BatchNumber, ExpiryDate: l.ExpiryDate, Quantity: -l.Quantity})
+		}
+
+		for _, in := range transferMoves(item, shipped) {
+			in.OutletID = transitID
+			in.Reason = models.StockReasonTransferIn
+			in.ReferenceID = id
+			in.Note = "dalam perjalanan ke " + toName
+			in.CreatedBy = by
+			if err := moveStock(tx, &in); err != nil {
+				return fmt.Errorf("product %s: %w", item.ProductName, err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		"UPDATE stock_transfers SET status = $1, shipped_by = $2, shipped_at = CURRENT_TIMESTAMP WHERE id = $3",
+		models.StockTransferStatusShipped, by, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Receive buat nerima transfer yang udah dikirim. Stock keluar dari lokasi transit (transfer_out) dan masuk ke
+// outlet tujuan (transfer_in), lot yang dikirim masuk ke batch dan tanggal kadaluarsa yang sama di outlet tujuan
+func (r *StockTransferRepository) Receive(id int, by string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, fromOutletID, toOutletID, err := lockStockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.StockTransferStatusShipped {
+		return fmt.Errorf("cannot receive stock transfer with status %s", status)
+	}
+	_, fromName, err := resolveOutlet(tx, fromOutletID)
+	if err != nil {
+		return err
+	}
+	_, toName, err := resolveOutlet(tx, toOutletID)
+	if err != nil {
+		return err
+	}
+	transitID, err := transitOutlet(tx)
+	if err != nil {
+		return err
+	}
+
+	items, err := getLockedStockTransferItems(tx, id)
+	if err != nil {
+		return err
+	}
+	itemIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		itemIDs = append(itemIDs, int64(item.ID))
+	}
+	lots, err := getStockTransferLots(tx, itemIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		for _, in := range transferMoves(item, lots[item.ID]) {
+			// Batch yang dikirim diambil dari lot yang sama di lokasi transit, tanpa batch diambil FEFO
+			out := in
+			out.OutletID = transitID
+			out.Quantity = -in.Quantity
+			out.Reason = models.StockReasonTransferOut
+			out.ReferenceID = id
+			out.Note = "diterima di " + toName
+			out.CreatedBy = by
+			if len(lots[item.ID]) > 0 {
+				if out.LotID, err = findOrCreateLot(tx, item.ProductID, transitID, in.BatchNumber, in.ExpiryDate); err != nil {
+					return err
+				}
+			}
+			if err := moveStock(tx, &out); err != nil {
+				return fmt.Errorf("product %s: %w", item.ProductName, err)
+			}
+
+			in.OutletID = toOutletID
+			in.Reason = models.StockReasonTransferIn
+			in.ReferenceID = id
+			in.Note = "transfer dari " + fromName
+			in.CreatedBy = by
+			if err := moveStock(tx, &in); err != nil {
+				return fmt.Errorf("product %s: %w", item.ProductName, err)
+			}
+		}
+	}
+
+	_, err = tx.Exec(
+		"UPDATE stock_transfers SET status = $1, received_by = $2, received_at = CURRENT_TIMESTAMP WHERE id = $3",
+		models.StockTransferStatusReceived, by, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// transferMoves buat mecah satu item transfer jadi baris ledger yang masuk ke satu outlet. Produk tanpa lot
+// cukup satu baris, produk track_lots satu baris per batch yang dikirim. Outlet, reason dan catatannya diisi pemanggil
+func transferMoves(item models.StockTransferItem, lots []models.StockTransferLot) []models.StockMovement {
+	base := models.StockMovement{ProductID: item.ProductID, ReferenceType: models.StockReferenceTransfer}
+	if len(lots) == 0 {
+		base.Quantity = item.Quantity
+		return []models.StockMovement{base}
+	}
+
+	moves := make([]models.StockMovement, 0, len(lots))
+	for _, l := range lots {
+		m := base
+		m.Quantity, m.BatchNumber, m.ExpiryDate = l.Quantity, l.BatchNumber, l.ExpiryDate
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// Cancel buat batalin transfer yang belum dikirim, stock belum berubah jadi nggak ada yang perlu dibalikin.
+// Transfer yang udah dikirim stock-nya ada di lokasi transit, harus diterima dulu
+func (r *StockTransferRepository) Cancel(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, _, _, err := lockStockTransfer(tx, id)
+	if err != nil {
+		return err
+	}
+	if status != models.StockTransferStatusRequested {
+		return fmt.Errorf("cannot cancel stock transfer with status %s", status)
+	}
+
+	if _, err := tx.Exec("UPDATE stock_transfers SET status = $1 WHERE id = $2", models.StockTransferStatusCancelled, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type SupplierRepository struct {
+	db *sql.DB
+}
+
+// NewSupplierRepository buat bikin instance repository baru
+func NewSupplierRepository(db *sql.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+// GetAll buat ambil semua suppliers dari database
+func (r *SupplierRepository) GetAll() ([]models.Supplier, error) {
+	query := "SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(address, '') FROM suppliers ORDER BY id"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := make([]models.Supplier, 0)
+	for rows.Next() {
+		var s models.Supplier
+		err := rows.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	return suppliers, rows.Err()
+}
+
+// GetByID buat ambil supplier berdasarkan ID
+func (r *SupplierRepository) GetByID(id int) (*models.Supplier, error) {
+	query := "SELECT id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(address, '') FROM suppliers WHERE id = $1"
+
+	var s models.Supplier
+	err := r.db.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("supplier not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Create buat bikin supplier baru
+func (r *SupplierRepository) Create(supplier *models.Supplier) error {
+	query := "INSERT INTO suppliers (name, phone, email, address) VALUES ($1, $2, $3, $4) RETURNING id"
+	err := r.db.QueryRow(query, supplier.Name, supplier.Phone, supplier.Email, supplier.Address).Scan(&supplier.ID)
+	return err
+}
+
+// Update buat update supplier yang udah ada
+func (r *SupplierRepository) Update(supplier *models.Supplier) error {
+	query := "UPDATE suppliers SET name = $1, phone = $2, email = $3, address = $4 WHERE id = $5"
+	result, err := r.db.Exec(query, supplier.Name, supplier.Phone, supplier.Email, supplier.Address, supplier.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("supplier not found")
+	}
+
+	return nil
+}
+
+// Delete buat hapus supplier
+func (r *SupplierRepository) Delete(id int) error {
+	query := "DELETE FROM suppliers WHERE id = $1"
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("supplier not found")
+	}
+
+	return nil
+}
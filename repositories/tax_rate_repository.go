@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+type TaxRateRepository struct {
+	db *sql.DB
+}
+
+// NewTaxRateRepository buat bikin instance repository baru
+func NewTaxRateRepository(db *sql.DB) *TaxRateRepository {
+	return &TaxRateRepository{db: db}
+}
+
+// GetAll buat ambil semua tax rates dari database
+func (r *TaxRateRepository) GetAll() ([]models.TaxRate, error) {
+	query := "SELECT id, name, rate, inclusive, is_default FROM tax_rates ORDER BY id"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	taxRates := make([]models.TaxRate, 0)
+	for rows.Next() {
+		var t models.TaxRate
+		err := rows.Scan(&t.ID, &t.Name, &t.Rate, &t.Inclusive, &t.IsDefault)
+		if err != nil {
+			return nil, err
+		}
+		taxRates = append(taxRates, t)
+	}
+
+	return taxRates, rows.Err()
+}
+
+// GetByID buat ambil tax rate berdasarkan ID
+func (r *TaxRateRepository) GetByID(id int) (*models.TaxRate, error) {
+	query := "SELECT id, name, rate, inclusive, is_default FROM tax_rates WHERE id = $1"
+
+	var t models.TaxRate
+	err := r.db.QueryRow(query, id).Scan(&t.ID, &t.Name, &t.Rate, &t.Inclusive, &t.IsDefault)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("tax rate not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Create buat bikin tax rate baru. Kalau is_default, tarif default yang lama dicabut dulu
+func (r *TaxRateRepository) Create(taxRate *models.TaxRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if taxRate.IsDefault {
+		if _, err := tx.Exec("UPDATE tax_rates SET is_default = FALSE WHERE is_default"); err != nil {
+			return err
+		}
+	}
+
+	query := "INSERT INTO tax_rates (name, rate, inclusive, is_default) VALUES ($1, $2, $3, $4) RETURNING id"
+	err = tx.QueryRow(query, taxRate.Name, taxRate.Rate, taxRate.Inclusive, taxRate.IsDefault).Scan(&taxRate.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update buat update tax rate yang udah ada. Kalau is_default, tarif default yang lama dicabut dulu
+func (r *TaxRateRepository) Update(taxRate *models.TaxRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if taxRate.IsDefault {
+		if _, err := tx.Exec("UPDATE tax_rates SET is_default = FALSE WHERE is_default AND id <> $1", taxRate.ID); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE tax_rates SET name = $1, rate = $2, inclusive = $3, is_default = $4 WHERE id = $5"
+	result, err := tx.Exec(query, taxRate.Name, taxRate.Rate, taxRate.Inclusive, taxRate.IsDefault, taxRate.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("tax rate not found")
+	}
+
+	return tx.Commit()
+}
+
+// Delete buat hapus tax rate
+func (r *TaxRateRepository) Delete(id int) error {
+	query := "DELETE FROM tax_rates WHERE id = $1"
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errors.New("tax rate not found")
+	}
+
+	return nil
+}
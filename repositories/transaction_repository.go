@@ -2,11 +2,23 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
 )
 
+// ErrIdempotencyKeyExists dikembalikan kalau Idempotency-Key udah dipakai checkout lain
+var ErrIdempotencyKeyExists = errors.New("idempotency key already used")
+
+// ErrTransactionNotFound dikembalikan kalau transaksi dengan ID itu nggak ada
+var ErrTransactionNotFound = errors.New("transaction not found")
+
 type TransactionRepository struct {
 	db *sql.DB
 }
@@ -16,77 +28,917 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-// CreateTransaction buat bikin transaksi baru dengan multiple items
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
+// Pricer itu hook buat ngitung harga akhir transaksi (promo dan sejenisnya) sebelum disimpan.
+// Dipanggil setelah semua produk di-lock, Details udah berisi harga kotor dan TotalAmount = GrossAmount
+type Pricer func(transaction *models.Transaction) error
+
+// CreateTransaction buat bikin transaksi baru dengan multiple items dan pembayarannya.
+// pricer boleh nil kalau harga cukup price * quantity
+func (repo *TransactionRepository) CreateTransaction(req models.CheckoutRequest, pricer Pricer) (*models.Transaction, error) {
 	tx, err := repo.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	totalAmount := 0
-	details := make([]models.TransactionDetail, 0)
+	// Key di-insert paling awal, checkout barengan dengan key yang sama bakal nunggu di sini
+	// sampai yang pertama selesai, baru dapet konflik
+	if req.IdempotencyKey != "" {
+		var inserted string
+		err = tx.QueryRow(
+			"INSERT INTO idempotency_keys (key, request_hash) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING RETURNING key",
+			req.IdempotencyKey, req.RequestHash,
+		).Scan(&inserted)
+		if err == sql.ErrNoRows {
+			return nil, ErrIdempotencyKeyExists
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+	outletID, outletName, err := resolveOutlet(tx, req.OutletID)
+	if err != nil {
+		return nil, err
+	}
 
-		err := tx.QueryRow("SELECT name, price, stock FROM products WHERE id = $1", item.ProductID).Scan(&productName, &productPrice, &stock)
+	var customerName string
+	if req.CustomerID != 0 {
+		err := tx.QueryRow("SELECT name FROM customers WHERE id = $1", req.CustomerID).Scan(&customerName)
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, errors.New("customer not found")
 		}
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	if err := resolveBarcodes(tx, req.Items); err != nil {
+		return nil, err
+	}
 
-		// Check stock
-		if stock < item.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)", productName, stock, item.Quantity)
+	items, err := mergeCheckoutItems(req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Isi paket dibaca dulu biar komponennya ikut di-lock bareng produk lain dalam urutan ID
+	bundleComponents, err := getBundleComponents(tx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, int64(item.ProductID))
+		for _, c := range bundleComponents[item.ProductID] {
+			productIDs = append(productIDs, int64(c.ProductID))
 		}
+	}
 
-		subtotal := productPrice * item.Quantity
-		totalAmount += subtotal
+	products, err := lockProducts(tx, productIDs, outletID)
+	if err != nil {
+		return nil, err
+	}
 
-		_, err = tx.Exec("UPDATE products SET stock = stock - $1 WHERE id = $2", item.Quantity, item.ProductID)
-		if err != nil {
+	// Row customer di-lock setelah produk, sama kayak urutan di refund, biar poinnya nggak bisa ditukar dua kali
+	if req.CustomerID != 0 {
+		if err := lockCustomer(tx, req.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+	if req.RedeemPoints > 0 {
+		if _, err := expireCustomerPoints(tx, req.CustomerID); err != nil {
+			return nil, err
+		}
+		var points int
+		if err := tx.QueryRow("SELECT points FROM customers WHERE id = $1", req.CustomerID).Scan(&points); err != nil {
 			return nil, err
 		}
+		if points < req.RedeemPoints {
+			return nil, fmt.Errorf("insufficient points (available: %d, requested: %d)", points, req.RedeemPoints)
+		}
+	}
+
+	// Gift card di-lock paling akhir, saldonya baru dipotong setelah transaksinya ke-insert
+	giftCardIDs, err := lockGiftCards(tx, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Satuan dibaca setelah produknya di-lock, update satuan juga nge-lock row produk
+	itemProductIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		itemProductIDs = append(itemProductIDs, int64(item.ProductID))
+	}
+	productUnits, err := getProductUnits(tx, itemProductIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stock yang dibutuhin dihitung per produk fisik dalam satuan dasar, jadi produk yang dijual
+	// langsung sekaligus jadi isi paket di keranjang yang sama tetap dicek totalnya
+	required := make(map[int]int)
+	itemUnits := make([]models.ProductUnit, len(items))
+	baseQuantities := make([]int, len(items))
+	for i, item := range items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+		}
+		unit, err := findUnit(product.baseUnit, product.price, productUnits[item.ProductID], item.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", product.name, err)
+		}
+		quantity, err := toBaseQuantity(item.Quantity, unit, product.baseUnit)
+		if err != nil {
+			return nil, fmt.Errorf("product %s: %w", product.name, err)
+		}
+		itemUnits[i], baseQuantities[i] = unit, quantity
 
-		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: productName,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
+		if !product.isBundle {
+			required[item.ProductID] += quantity
+			continue
+		}
+		if len(bundleComponents[item.ProductID]) == 0 {
+			return nil, fmt.Errorf("bundle %s has no components", product.name)
+		}
+		for _, c := range bundleComponents[item.ProductID] {
+			required[c.ProductID] += c.Quantity * quantity
+		}
+	}
+
+	transaction := &models.Transaction{
+		OutletID:       outletID,
+		OutletName:     outletName,
+		CustomerID:     req.CustomerID,
+		CustomerName:   customerName,
+		PointsRedeemed: req.RedeemPoints,
+		Status:         models.TransactionStatusCompleted,
+		Details:        make([]models.TransactionDetail, 0),
+		Promotions:     make([]models.AppliedPromotion, 0),
+	}
+
+	for i, item := range items {
+		product := products[item.ProductID]
+		if product.hasVariants {
+			return nil, fmt.Errorf("product %s has variants, choose a variant to sell", product.name)
+		}
+
+		// Check stock di outlet kasir
+		if !product.isBundle && product.stock < required[item.ProductID] {
+			return nil, fmt.Errorf("insufficient stock for product %s at outlet %s (available: %d, requested: %d)",
+				product.name, outletName, product.stock, required[item.ProductID])
+		}
+
+		// HPP paket itu jumlah HPP komponennya, komponennya disnapshot buat struk dan refund
+		unitCost := product.costPrice
+		var components []models.TransactionDetailComponent
+		if product.isBundle {
+			unitCost = 0
+			for _, c := range bundleComponents[item.ProductID] {
+				component, ok := products[c.ProductID]
+				if !ok {
+					return nil, fmt.Errorf("bundle component product id %d not found", c.ProductID)
+				}
+				if component.stock < required[c.ProductID] {
+					return nil, fmt.Errorf("insufficient stock for product %s in bundle %s at outlet %s (available: %d, requested: %d)",
+						component.name, product.name, outletName, component.stock, required[c.ProductID])
+				}
+				unitCost += component.costPrice * c.Quantity
+				components = append(components, models.TransactionDetailComponent{
+					ProductID:   c.ProductID,
+					ProductName: component.name,
+					Quantity:    c.Quantity,
+					UnitCost:    component.costPrice,
+				})
+			}
+		}
+
+		// Harga per satuan yang dipakai, jumlah desimal (barang timbangan) dibulatkan ke rupiah terdekat
+		subtotal := int(math.Round(float64(itemUnits[i].Price) * item.Quantity))
+		transaction.GrossAmount += subtotal
+
+		transaction.Details = append(transaction.Details, models.TransactionDetail{
+			ProductID:         item.ProductID,
+			ProductName:       product.name,
+			SKU:               product.sku,
+			ParentProductID:   product.parentID,
+			ParentProductName: product.parentName,
+			CategoryID:        product.categoryID,
+			CategoryName:      product.categoryName,
+			UnitPrice:         itemUnits[i].Price,
+			TaxRateID:         product.taxRateID,
+			TaxExempt:         product.taxExempt,
+			Quantity:          baseQuantities[i],
+			Unit:              itemUnits[i].Name,
+			UnitQuantity:      item.Quantity,
+			GrossSubtotal:     subtotal,
+			Subtotal:          subtotal,
+			Total:             subtotal,
+			UnitCost:          unitCost,
+			Components:        components,
 		})
 	}
+	transaction.SubtotalAmount = transaction.GrossAmount
+	transaction.TotalAmount = transaction.GrossAmount
 
-	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+	if pricer != nil {
+		if err := pricer(transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	transaction.Payments, transaction.PaidAmount, transaction.ChangeAmount, err = settlePayments(transaction.TotalAmount, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(
+		`INSERT INTO transactions (outlet_id, customer_id, gross_amount, discount_amount, subtotal_amount, tax_amount, service_charge_amount,
+		 total_amount, paid_amount, change_amount, points_redeemed, points_discount, points_earned)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, created_at`,
+		transaction.OutletID, nullInt(transaction.CustomerID), transaction.GrossAmount, transaction.DiscountAmount, transaction.SubtotalAmount, transaction.TaxAmount,
+		transaction.ServiceChargeAmount, transaction.TotalAmount, transaction.PaidAmount, transaction.ChangeAmount,
+		transaction.PointsRedeemed, transaction.PointsDiscount, transaction.PointsEarned,
+	).Scan(&transaction.ID, &transaction.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	// FIX: Insert transaction details dengan RETURNING id untuk dapetin ID nya
-	for i := range details {
-		details[i].TransactionID = transactionID
-		var detailID int
+	for i := range transaction.Details {
+		d := &transaction.Details[i]
+		d.TransactionID = transaction.ID
+		err = tx.QueryRow(
+			`INSERT INTO transaction_details (transaction_id, product_id, product_name, sku, parent_product_id, parent_product_name,
+			 category_id, category_name, unit_price, quantity, gross_subtotal, discount_amount, promotion_id,
+			 subtotal, tax_rate_id, tax_rate, tax_inclusive, tax_amount, service_charge_amount, total, unit_cost, unit, unit_quantity)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23) RETURNING id`,
+			transaction.ID, d.ProductID, d.ProductName, d.SKU, nullInt(d.ParentProductID), d.ParentProductName,
+			nullInt(d.CategoryID), d.CategoryName, d.UnitPrice,
+			d.Quantity, d.GrossSubtotal, d.DiscountAmount, nullInt(d.PromotionID),
+			d.Subtotal, nullInt(d.TaxRateID), d.TaxRate, d.TaxInclusive, d.TaxAmount, d.ServiceChargeAmount, d.Total, d.UnitCost,
+			d.Unit, d.UnitQuantity,
+		).Scan(&d.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Stock dikurangi setelah transaksi punya ID biar ledger-nya bisa nunjuk ke transaksi ini.
+		// Row produk udah di-lock di lockProducts, jadi stock yang dicek di atas masih berlaku
+		if len(d.Components) == 0 {
+			err = moveStock(tx, &models.StockMovement{
+				ProductID:     d.ProductID,
+				OutletID:      transaction.OutletID,
+				Quantity:      -d.Quantity,
+				Reason:        models.StockReasonSale,
+				ReferenceType: models.StockReferenceTransaction,
+				ReferenceID:   transaction.ID,
+				SkipExpired:   !req.AllowExpired,
+			})
+			if err == ErrInsufficientStock {
+				return nil, fmt.Errorf("insufficient stock for product %s", d.ProductName)
+			}
+			if err == ErrExpiredStock {
+				return nil, fmt.Errorf("product %s: %w", d.ProductName, err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Paket: yang tercatat di struk baris paketnya, yang berkurang stock tiap komponen
+		for _, c := range d.Components {
+			_, err = tx.Exec(
+				`INSERT INTO transaction_detail_components (transaction_detail_id, product_id, product_name, quantity, unit_cost)
+				 VALUES ($1, $2, $3, $4, $5)`,
+				d.ID, c.ProductID, c.ProductName, c.Quantity, c.UnitCost,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			err = moveStock(tx, &models.StockMovement{
+				ProductID:     c.ProductID,
+				OutletID:      transaction.OutletID,
+				Quantity:      -c.Quantity * d.Quantity,
+				Reason:        models.StockReasonSale,
+				ReferenceType: models.StockReferenceTransaction,
+				ReferenceID:   transaction.ID,
+				Note:          "paket: " + d.ProductName,
+				SkipExpired:   !req.AllowExpired,
+			})
+			if err == ErrInsufficientStock {
+				return nil, fmt.Errorf("insufficient stock for product %s in bundle %s", c.ProductName, d.ProductName)
+			}
+			if err == ErrExpiredStock {
+				return nil, fmt.Errorf("product %s in bundle %s: %w", c.ProductName, d.ProductName, err)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, p := range transaction.Promotions {
+		_, err = tx.Exec(
+			"INSERT INTO transaction_promotions (transaction_id, promotion_id, promotion_name, scope, amount) VALUES ($1, $2, $3, $4, $5)",
+			transaction.ID, nullInt(p.PromotionID), p.Name, p.Scope, p.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range transaction.Payments {
+		p := &transaction.Payments[i]
+		p.TransactionID = transaction.ID
+		if p.Method == models.PaymentMethodGiftCard {
+			p.GiftCardID = giftCardIDs[p.GiftCardCode]
+			err := moveGiftCardBalance(tx, &models.GiftCardEntry{
+				GiftCardID:    p.GiftCardID,
+				Amount:        -p.Amount,
+				Reason:        models.GiftCardReasonRedeem,
+				TransactionID: transaction.ID,
+			})
+			if err == ErrInsufficientGiftCardBalance {
+				return nil, fmt.Errorf("%w for %s", err, maskGiftCardCode(p.GiftCardCode))
+			}
+			if err != nil {
+				return nil, err
+			}
+			p.GiftCardCode = maskGiftCardCode(p.GiftCardCode)
+		}
 		err = tx.QueryRow(
-			"INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4) RETURNING id",
-			transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal,
-		).Scan(&detailID)
+			"INSERT INTO transaction_payments (transaction_id, method, amount, tendered, gift_card_id) VALUES ($1, $2, $3, $4, $5) RETURNING id",
+			transaction.ID, p.Method, p.Amount, p.Tendered, nullInt(p.GiftCardID),
+		).Scan(&p.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Poin yang ditukar dipotong dulu dari saldo, baru poin dari belanjaan ini ditambah
+	if transaction.CustomerID != 0 && transaction.PointsRedeemed > 0 {
+		err := movePoints(tx, &models.PointEntry{
+			CustomerID:    transaction.CustomerID,
+			Points:        -transaction.PointsRedeemed,
+			Reason:        models.PointReasonRedeem,
+			TransactionID: transaction.ID,
+		}, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if transaction.CustomerID != 0 && transaction.PointsEarned > 0 {
+		err := movePoints(tx, &models.PointEntry{
+			CustomerID:    transaction.CustomerID,
+			Points:        transaction.PointsEarned,
+			Reason:        models.PointReasonEarn,
+			TransactionID: transaction.ID,
+		}, req.PointsExpiryDays)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Simpan response aslinya biar retry dapet jawaban yang persis sama
+	if req.IdempotencyKey != "" {
+		responseBody, err := json.Marshal(transaction)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(
+			"UPDATE idempotency_keys SET transaction_id = $1, response_body = $2 WHERE key = $3",
+			transaction.ID, string(responseBody), req.IdempotencyKey,
+		)
 		if err != nil {
 			return nil, err
 		}
-		details[i].ID = detailID
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &models.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
-	}, nil
+	return transaction, nil
+}
+
+// GetIdempotencyKey buat ambil data Idempotency-Key, balikin nil kalau key belum pernah dipakai
+func (repo *TransactionRepository) GetIdempotencyKey(key string) (*models.IdempotencyKey, error) {
+	var k models.IdempotencyKey
+	var transactionID sql.NullInt64
+	var responseBody sql.NullString
+	err := repo.db.QueryRow(
+		"SELECT key, request_hash, transaction_id, response_body FROM idempotency_keys WHERE key = $1", key,
+	).Scan(&k.Key, &k.RequestHash, &transactionID, &responseBody)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k.TransactionID = int(transactionID.Int64)
+	k.ResponseBody = []byte(responseBody.String)
+	return &k, nil
+}
+
+// lockedProduct itu data produk yang row-nya udah di-lock selama checkout
+type lockedProduct struct {
+	name         string
+	sku          string
+	parentID     int
+	parentName   string
+	hasVariants  bool
+	isBundle     bool
+	baseUnit     string
+	categoryName string
+	price        int
+	costPrice    int
+	stock        int
+	categoryID   int
+	taxRateID    int
+	taxExempt    bool
+}
+
+// mergeCheckoutItems buat gabungin product_id dengan satuan yang sama yang muncul lebih dari sekali
+// di satu request, urutan kemunculan pertama tetap dipertahankan
+func mergeCheckoutItems(items []models.CheckoutItem) ([]models.CheckoutItem, error) {
+	type itemKey struct {
+		productID int
+		unit      string
+	}
+	merged := make([]models.CheckoutItem, 0, len(items))
+	indexByProduct := make(map[itemKey]int)
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity for product id %d must be greater than 0", item.ProductID)
+		}
+		key := itemKey{item.ProductID, item.Unit}
+		if i, ok := indexByProduct[key]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		indexByProduct[key] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged, nil
+}
+
+// resolveBarcodes buat ngisi ProductID item checkout yang ditunjuk pakai barcode.
+// Barcode harus udah dinormalisasi jadi EAN-13
+func resolveBarcodes(tx *sql.Tx, items []models.CheckoutItem) error {
+	barcodes := make([]string, 0)
+	for _, item := range items {
+		if item.Barcode != "" {
+			barcodes = append(barcodes, item.Barcode)
+		}
+	}
+	if len(barcodes) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query("SELECT barcode, product_id FROM product_barcodes WHERE barcode = ANY($1)", pq.Array(barcodes))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	productIDs := make(map[string]int)
+	for rows.Next() {
+		var barcode string
+		var productID int
+		if err := rows.Scan(&barcode, &productID); err != nil {
+			return err
+		}
+		productIDs[barcode] = productID
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range items {
+		if items[i].Barcode == "" {
+			continue
+		}
+		productID, ok := productIDs[items[i].Barcode]
+		if !ok {
+			return fmt.Errorf("product with barcode %s not found", items[i].Barcode)
+		}
+		items[i].ProductID = productID
+	}
+	return nil
+}
+
+// getBundleComponents buat ambil isi paket dari item checkout yang produknya paket, per ID paket
+func getBundleComponents(tx *sql.Tx, items []models.CheckoutItem) (map[int][]models.BundleComponent, error) {
+	productIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, int64(item.ProductID))
+	}
+
+	rows, err := tx.Query(
+		"SELECT bundle_id, component_id, quantity FROM bundle_components WHERE bundle_id = ANY($1) ORDER BY id",
+		pq.Array(productIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	components := make(map[int][]models.BundleComponent)
+	for rows.Next() {
+		var bundleID int
+		var c models.BundleComponent
+		if err := rows.Scan(&bundleID, &c.ProductID, &c.Quantity); err != nil {
+			return nil, err
+		}
+		components[bundleID] = append(components[bundleID], c)
+	}
+	return components, rows.Err()
+}
+
+// lockProducts buat lock row semua produk di checkout pakai SELECT ... FOR UPDATE. Lock diambil
+// urut ID biar dua checkout dengan produk yang sama nggak saling nunggu (deadlock).
+// Stock yang dibalikin stock di outlet outletID, semua perubahan stock outlet juga nge-lock row produknya dulu
+func lockProducts(tx *sql.Tx, productIDs []int64, outletID int) (map[int]lockedProduct, error) {
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i] < productIDs[j] })
+
+	// Tarif pajak produk diutamakan, kalau kosong ikut tarif kategorinya
+	query := `SELECT p.id, p.name, COALESCE(p.sku, ''), COALESCE(p.parent_id, 0), COALESCE(pp.name, ''),
+			  EXISTS (SELECT 1 FROM products v WHERE v.parent_id = p.id), p.is_bundle, p.base_unit,
+			  COALESCE(c.name, ''), p.price, p.cost_price, COALESCE(os.stock, 0),
+			  COALESCE(p.category_id, 0),
+			  COALESCE(p.tax_rate_id, c.tax_rate_id, 0), p.tax_exempt
+			  FROM products p
+			  LEFT JOIN products pp ON p.parent_id = pp.id
+			  LEFT JOIN categories c ON p.category_id = c.id
+			  LEFT JOIN outlet_stocks os ON os.product_id = p.id AND os.outlet_id = $2
+			  WHERE p.id = ANY($1)
+			  ORDER BY p.id
+			  FOR UPDATE OF p`
+
+	rows, err := tx.Query(query, pq.Array(productIDs), outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[int]lockedProduct)
+	for rows.Next() {
+		var id int
+		var p lockedProduct
+		err := rows.Scan(&id, &p.name, &p.sku, &p.parentID, &p.parentName, &p.hasVariants, &p.isBundle, &p.baseUnit, &p.categoryName, &p.price, &p.costPrice,
+			&p.stock, &p.categoryID, &p.taxRateID, &p.taxExempt)
+		if err != nil {
+			return nil, err
+		}
+		products[id] = p
+	}
+
+	return products, rows.Err()
+}
+
+// settlePayments buat ngecek pembayaran cukup buat nutup total dan ngitung kembalian.
+// Kembalian cuma boleh dari cash, jadi pembayaran non-cash nggak boleh lebih dari total
+func settlePayments(totalAmount int, checkoutPayments []models.CheckoutPayment) ([]models.Payment, int, int, error) {
+	nonCash, cash := 0, 0
+	for _, p := range checkoutPayments {
+		if p.Method == models.PaymentMethodCash {
+			cash += p.Amount
+		} else {
+			nonCash += p.Amount
+		}
+	}
+
+	if nonCash > totalAmount {
+		return nil, 0, 0, fmt.Errorf("non-cash payments (%d) exceed total amount (%d)", nonCash, totalAmount)
+	}
+	paidAmount := nonCash + cash
+	if paidAmount < totalAmount {
+		return nil, 0, 0, fmt.Errorf("insufficient payment (total: %d, paid: %d)", totalAmount, paidAmount)
+	}
+
+	// Sisa tagihan setelah non-cash ditutup pakai cash sesuai urutan, kelebihannya jadi kembalian
+	remaining := totalAmount - nonCash
+	payments := make([]models.Payment, 0, len(checkoutPayments))
+	for _, p := range checkoutPayments {
+		applied := p.Amount
+		if p.Method == models.PaymentMethodCash {
+			applied = min(p.Amount, remaining)
+			remaining -= applied
+		}
+		payments = append(payments, models.Payment{
+			Method:       p.Method,
+			Amount:       applied,
+			Tendered:     p.Amount,
+			GiftCardCode: p.GiftCardCode,
+		})
+	}
+
+	return payments, paidAmount, paidAmount - totalAmount, nil
+}
+
+const transactionColumns = `t.id, COALESCE(t.outlet_id, 0), COALESCE(o.name, ''), COALESCE(t.customer_id, 0), COALESCE(c.name, ''), t.gross_amount, t.discount_amount, t.subtotal_amount, t.tax_amount,
+	t.service_charge_amount, t.total_amount, t.paid_amount, t.change_amount, t.points_redeemed, t.points_discount, t.points_earned,
+	t.status, t.created_at`
+
+// scanTransaction buat scan satu row transactions sesuai urutan transactionColumns
+func scanTransaction(row interface{ Scan(...interface{}) error }, t *models.Transaction) error {
+	return row.Scan(&t.ID, &t.OutletID, &t.OutletName, &t.CustomerID, &t.CustomerName, &t.GrossAmount, &t.DiscountAmount, &t.SubtotalAmount, &t.TaxAmount,
+		&t.ServiceChargeAmount, &t.TotalAmount, &t.PaidAmount, &t.ChangeAmount, &t.PointsRedeemed, &t.PointsDiscount, &t.PointsEarned,
+		&t.Status, &t.CreatedAt)
+}
+
+// GetAll buat ambil riwayat transaksi pakai filter dan pagination
+func (repo *TransactionRepository) GetAll(filter models.TransactionFilter) ([]models.Transaction, int, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.StartDate != "" {
+		args = append(args, filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf("DATE(t.created_at) >= $%d", len(args)))
+	}
+	if filter.EndDate != "" {
+		args = append(args, filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf("DATE(t.created_at) <= $%d", len(args)))
+	}
+	if filter.OutletID != 0 {
+		args = append(args, filter.OutletID)
+		conditions = append(conditions, fmt.Sprintf("t.outlet_id = $%d", len(args)))
+	}
+	if filter.CustomerID != 0 {
+		args = append(args, filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("t.customer_id = $%d", len(args)))
+	}
+	if filter.ProductID != 0 {
+		args = append(args, filter.ProductID)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM transaction_details td WHERE td.transaction_id = t.id AND td.product_id = $%d)", len(args)))
+	}
+	if filter.MinAmount != 0 {
+		args = append(args, filter.MinAmount)
+		conditions = append(conditions, fmt.Sprintf("t.total_amount >= $%d", len(args)))
+	}
+	if filter.MaxAmount != 0 {
+		args = append(args, filter.MaxAmount)
+		conditions = append(conditions, fmt.Sprintf("t.total_amount <= $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := repo.db.QueryRow("SELECT COUNT(*) FROM transactions t"+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	query := fmt.Sprintf(`SELECT `+transactionColumns+`
+			  FROM transactions t
+			  LEFT JOIN outlets o ON o.id = t.outlet_id
+			  LEFT JOIN customers c ON c.id = t.customer_id%s
+			  ORDER BY t.created_at DESC, t.id DESC
+			  LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	rows, err := repo.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := make([]models.Transaction, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var t models.Transaction
+		err := scanTransaction(rows, &t)
+		if err != nil {
+			return nil, 0, err
+		}
+		t.Details = make([]models.TransactionDetail, 0)
+		t.Promotions = make([]models.AppliedPromotion, 0)
+		t.Payments = make([]models.Payment, 0)
+		transactions = append(transactions, t)
+		ids = append(ids, int64(t.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	details, err := repo.getDetails(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	promotions, err := repo.getPromotions(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	payments, err := repo.getPayments(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range transactions {
+		transactions[i].Details = append(transactions[i].Details, details[transactions[i].ID]...)
+		transactions[i].Promotions = append(transactions[i].Promotions, promotions[transactions[i].ID]...)
+		transactions[i].Payments = append(transactions[i].Payments, payments[transactions[i].ID]...)
+	}
+
+	return transactions, total, nil
+}
+
+// GetByID buat ambil satu transaksi lengkap dengan detailnya
+func (repo *TransactionRepository) GetByID(id int) (*models.Transaction, error) {
+	var t models.Transaction
+	err := scanTransaction(repo.db.QueryRow(
+		`SELECT `+transactionColumns+`
+		 FROM transactions t
+		 LEFT JOIN outlets o ON o.id = t.outlet_id
+		 LEFT JOIN customers c ON c.id = t.customer_id
+		 WHERE t.id = $1`, id), &t)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := repo.getDetails([]int64{int64(id)})
+	if err != nil {
+		return nil, err
+	}
+	t.Details = details[id]
+	if t.Details == nil {
+		t.Details = make([]models.TransactionDetail, 0)
+	}
+
+	promotions, err := repo.getPromotions([]int64{int64(id)})
+	if err != nil {
+		return nil, err
+	}
+	t.Promotions = promotions[id]
+	if t.Promotions == nil {
+		t.Promotions = make([]models.AppliedPromotion, 0)
+	}
+
+	payments, err := repo.getPayments([]int64{int64(id)})
+	if err != nil {
+		return nil, err
+	}
+	t.Payments = payments[id]
+	if t.Payments == nil {
+		t.Payments = make([]models.Payment, 0)
+	}
+
+	return &t, nil
+}
+
+// getDetails buat ambil detail beberapa transaksi sekaligus, dikelompokkan per transaction_id
+func (repo *TransactionRepository) getDetails(transactionIDs []int64) (map[int][]models.TransactionDetail, error) {
+	result := make(map[int][]models.TransactionDetail)
+	if len(transactionIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT td.id, td.transaction_id, COALESCE(td.product_id, 0), td.product_name, td.sku,
+			  COALESCE(td.parent_product_id, 0), td.parent_product_name, COALESCE(td.category_id, 0), td.category_name, td.unit_price, td.quantity, td.gross_subtotal, td.discount_amount, COALESCE(td.promotion_id, 0), td.subtotal,
+			  COALESCE(td.tax_rate_id, 0), td.tax_rate, td.tax_inclusive, td.tax_amount, td.service_charge_amount, td.total,
+			  td.unit_cost, td.unit, td.unit_quantity
+			  FROM transaction_details td
+			  WHERE td.transaction_id = ANY($1)
+			  ORDER BY td.id`
+
+	rows, err := repo.db.Query(query, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.TransactionDetail
+		err := rows.Scan(&d.ID, &d.TransactionID, &d.ProductID, &d.ProductName, &d.SKU,
+			&d.ParentProductID, &d.ParentProductName, &d.CategoryID, &d.CategoryName, &d.UnitPrice, &d.Quantity, &d.GrossSubtotal, &d.DiscountAmount, &d.PromotionID, &d.Subtotal,
+			&d.TaxRateID, &d.TaxRate, &d.TaxInclusive, &d.TaxAmount, &d.ServiceChargeAmount, &d.Total, &d.UnitCost, &d.Unit, &d.UnitQuantity)
+		if err != nil {
+			return nil, err
+		}
+		result[d.TransactionID] = append(result[d.TransactionID], d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := repo.loadDetailComponents(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadDetailComponents buat ngisi isi paket semua detail transaksi sekaligus
+func (repo *TransactionRepository) loadDetailComponents(details map[int][]models.TransactionDetail) error {
+	detailIDs := make([]int64, 0)
+	index := make(map[int]*models.TransactionDetail)
+	for transactionID := range details {
+		for i := range details[transactionID] {
+			d := &details[transactionID][i]
+			detailIDs = append(detailIDs, int64(d.ID))
+			index[d.ID] = d
+		}
+	}
+	if len(detailIDs) == 0 {
+		return nil
+	}
+
+	rows, err := repo.db.Query(
+		`SELECT transaction_detail_id, COALESCE(product_id, 0), product_name, quantity, unit_cost
+		 FROM transaction_detail_components
+		 WHERE transaction_detail_id = ANY($1)
+		 ORDER BY id`,
+		pq.Array(detailIDs),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var detailID int
+		var c models.TransactionDetailComponent
+		if err := rows.Scan(&detailID, &c.ProductID, &c.ProductName, &c.Quantity, &c.UnitCost); err != nil {
+			return err
+		}
+		d := index[detailID]
+		d.Components = append(d.Components, c)
+	}
+	return rows.Err()
+}
+
+// getPayments buat ambil pembayaran beberapa transaksi sekaligus, dikelompokkan per transaction_id
+func (repo *TransactionRepository) getPayments(transactionIDs []int64) (map[int][]models.Payment, error) {
+	result := make(map[int][]models.Payment)
+	if len(transactionIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT tp.id, tp.transaction_id, tp.method, tp.amount, tp.tendered, COALESCE(tp.gift_card_id, 0), COALESCE(g.code, '')
+			  FROM transaction_payments tp
+			  LEFT JOIN gift_cards g ON g.id = tp.gift_card_id
+			  WHERE tp.transaction_id = ANY($1)
+			  ORDER BY tp.id`
+
+	rows, err := repo.db.Query(query, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Payment
+		err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.Tendered, &p.GiftCardID, &p.GiftCardCode)
+		if err != nil {
+			return nil, err
+		}
+		if p.GiftCardCode != "" {
+			p.GiftCardCode = maskGiftCardCode(p.GiftCardCode)
+		}
+		result[p.TransactionID] = append(result[p.TransactionID], p)
+	}
+
+	return result, rows.Err()
+}
+
+// getPromotions buat ambil promo yang kepake di beberapa transaksi sekaligus, dikelompokkan per transaction_id
+func (repo *TransactionRepository) getPromotions(transactionIDs []int64) (map[int][]models.AppliedPromotion, error) {
+	result := make(map[int][]models.AppliedPromotion)
+	if len(transactionIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT transaction_id, COALESCE(promotion_id, 0), promotion_name, scope, amount
+			  FROM transaction_promotions
+			  WHERE transaction_id = ANY($1)
+			  ORDER BY id`
+
+	rows, err := repo.db.Query(query, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var transactionID int
+		var p models.AppliedPromotion
+		err := rows.Scan(&transactionID, &p.PromotionID, &p.Name, &p.Scope, &p.Amount)
+		if err != nil {
+			return nil, err
+		}
+		result[transactionID] = append(result[transactionID], p)
+	}
+
+	return result, rows.Err()
 }
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"database/sql"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/database"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/lib/pq"
+)
+
+// TestCreateTransactionConcurrentStock itu stress test checkout barengan ke database beneran.
+// Banyak goroutine checkout dua produk yang sama dengan urutan item acak (kadang product_id dobel di satu
+// request) sampai permintaannya jauh lebih banyak dari stock. Worker tetap jalan walaupun checkout-nya
+// ditolak karena stock nggak cukup, biar rebutan stock terakhir ikut kena. Di akhir dicek stock nggak minus,
+// stock berkurang persis sebanyak yang terjual, ledger cocok, dan jumlah transaksi yang tersimpan sama
+// dengan checkout yang berhasil. Di-skip kalau DB_CONN nggak diisi.
+//
+//	DB_CONN=postgres://... go test ./repositories -run TestCreateTransactionConcurrentStock
+func TestCreateTransactionConcurrentStock(t *testing.T) {
+	conn := os.Getenv("DB_CONN")
+	if conn == "" {
+		t.Skip("DB_CONN is not set")
+	}
+
+	const (
+		workers  = 20
+		attempts = 25
+		stock    = 100
+	)
+
+	db, err := database.InitDB(conn)
+	if err != nil {
+		t.Fatal("failed to connect database:", err)
+	}
+	defer db.Close()
+
+	productIDs := createStressProducts(t, db, stock)
+	repo := NewTransactionRepository(db)
+
+	var mu sync.Mutex
+	sold := make(map[int]int)
+	succeeded, rejected := 0, 0
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < attempts; i++ {
+				transaction, err := repo.CreateTransaction(models.CheckoutRequest{
+					Items:    randomStressItems(productIDs),
+					Payments: []models.CheckoutPayment{{Method: models.PaymentMethodCash, Amount: 1_000_000}},
+				}, nil)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					succeeded++
+					for _, d := range transaction.Details {
+						sold[d.ProductID] += d.Quantity
+					}
+				case strings.Contains(err.Error(), "insufficient stock"):
+					rejected++
+				default:
+					t.Errorf("unexpected checkout error: %v", err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rejected == 0 {
+		t.Errorf("expected some checkouts to be rejected for insufficient stock, demand never exceeded stock")
+	}
+
+	for _, id := range productIDs {
+		remaining, ledgerStock, err := NewStockMovementRepository(db).GetBalance(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if remaining < 0 {
+			t.Errorf("product %d: stock went negative (%d)", id, remaining)
+		}
+		if remaining != stock-sold[id] {
+			t.Errorf("product %d: stock %d, want %d (initial %d, sold %d)", id, remaining, stock-sold[id], stock, sold[id])
+		}
+		if ledgerStock != remaining {
+			t.Errorf("product %d: stock ledger sums to %d, stock is %d", id, ledgerStock, remaining)
+		}
+	}
+
+	var saved int
+	err = db.QueryRow(
+		"SELECT COUNT(DISTINCT transaction_id) FROM transaction_details WHERE product_id = ANY($1)", pq.Array(productIDs),
+	).Scan(&saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if succeeded == 0 || saved != succeeded {
+		t.Errorf("saved transactions %d, successful checkouts %d", saved, succeeded)
+	}
+}
+
+// createStressProducts buat bikin dua produk sementara, transaksi, ledger stock dan produknya dihapus lagi
+// setelah test. Transaksi dan ledger dihapus duluan karena masih nunjuk ke produknya. Produk test nggak
+// track_lots dan min_stock-nya 0, jadi nggak ada baris stock_movement_lots atau low_stock_alerts.
+// Aplikasinya sendiri nggak pernah hapus ledger, ini cuma buat bersihin data test
+func createStressProducts(t *testing.T, db *sql.DB, stock int) []int {
+	ids := make([]int, 0, 2)
+	t.Cleanup(func() {
+		for _, query := range []string{
+			"DELETE FROM transactions WHERE id IN (SELECT transaction_id FROM transaction_details WHERE product_id = ANY($1))",
+			"DELETE FROM stock_movements WHERE product_id = ANY($1)",
+			"DELETE FROM products WHERE id = ANY($1)",
+		} {
+			if _, err := db.Exec(query, pq.Array(ids)); err != nil {
+				t.Log("failed to clean up stress test data:", err)
+			}
+		}
+	})
+
+	for _, name := range []string{"Stress Test A", "Stress Test B"} {
+		product := models.Product{Name: name, Price: 1000, Stock: stock}
+		if err := NewProductRepository(db).Create(&product); err != nil {
+			t.Fatal("failed to create stress test product:", err)
+		}
+		ids = append(ids, product.ID)
+	}
+	return ids
+}
+
+// randomStressItems buat bikin item checkout dengan urutan acak, kadang product_id-nya dobel
+func randomStressItems(productIDs []int) []models.CheckoutItem {
+	items := make([]models.CheckoutItem, 0, 3)
+	for _, i := range rand.Perm(len(productIDs)) {
+		items = append(items, models.CheckoutItem{ProductID: productIDs[i], Quantity: float64(1 + rand.Intn(3))})
+	}
+	if rand.Intn(4) == 0 {
+		items = append(items, models.CheckoutItem{ProductID: productIDs[rand.Intn(len(productIDs))], Quantity: 1})
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidBarcode dikembalikan kalau barcode bukan EAN-13/UPC-A atau check digit-nya salah
+var ErrInvalidBarcode = errors.New("invalid barcode")
+
+// normalizeBarcode buat validasi barcode EAN-13 atau UPC-A (12 digit) termasuk check digit-nya.
+// UPC-A dijadiin EAN-13 dengan awalan 0, jadi scan versi mana pun ketemu produk yang sama
+func normalizeBarcode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("%w %q: must contain digits only", ErrInvalidBarcode, code)
+		}
+	}
+
+	switch len(code) {
+	case 12:
+		code = "0" + code
+	case 13:
+	default:
+		return "", fmt.Errorf("%w %q: must be EAN-13 (13 digits) or UPC-A (12 digits)", ErrInvalidBarcode, code)
+	}
+
+	if ean13CheckDigit(code[:12]) != code[12] {
+		return "", fmt.Errorf("%w %q: wrong check digit", ErrInvalidBarcode, code)
+	}
+	return code, nil
+}
+
+// ean13CheckDigit buat ngitung check digit dari 12 digit pertama EAN-13:
+// digit posisi genap dikali 3, check digit = angka yang bikin totalnya kelipatan 10
+func ean13CheckDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// normalizeBarcodes buat validasi semua barcode produk dan buang yang dobel
+func normalizeBarcodes(codes []string) ([]string, error) {
+	if codes == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		code, err := normalizeBarcode(code)
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// code128Patterns itu lebar bar dan spasi (dalam modul) buat tiap nilai Code128, selang-seling
+// mulai dari bar. 103-105 itu start code A/B/C, 106 itu stop (7 elemen)
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128StartB    = 104
+	code128StartC    = 105
+	code128Stop      = 106
+	code128QuietZone = 10 // modul kosong di kiri dan kanan biar scanner bisa nemu awal barcode
+)
+
+// encodeCode128 buat ubah teks jadi deretan modul Code128 (true = bar hitam), udah termasuk
+// quiet zone. Angka dengan jumlah digit genap pakai code set C biar barcode-nya lebih pendek,
+// selain itu pakai code set B (ASCII 32-126)
+func encodeCode128(text string) ([]bool, error) {
+	if text == "" {
+		return nil, errors.New("barcode text cannot be empty")
+	}
+
+	var values []int
+	if isDigits(text) && len(text)%2 == 0 {
+		values = append(values, code128StartC)
+		for i := 0; i < len(text); i += 2 {
+			values = append(values, int(text[i]-'0')*10+int(text[i+1]-'0'))
+		}
+	} else {
+		values = append(values, code128StartB)
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+			if c < 32 || c > 126 {
+				return nil, fmt.Errorf("character %q cannot be encoded in Code128", c)
+			}
+			values = append(values, int(c)-32)
+		}
+	}
+
+	checksum := values[0]
+	for i := 1; i < len(values); i++ {
+		checksum += i * values[i]
+	}
+	values = append(values, checksum%103, code128Stop)
+
+	modules := make([]bool, code128QuietZone)
+	for _, v := range values {
+		for i, w := range code128Patterns[v] {
+			for n := 0; n < int(w-'0'); n++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	modules = append(modules, make([]bool, code128QuietZone)...)
+
+	return modules, nil
+}
+
+func isDigits(v string) bool {
+	for _, c := range v {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return v != ""
+}
+
+// renderCode128PNG buat gambar barcode sebagai PNG hitam putih. moduleWidth itu lebar satu modul dalam pixel
+func renderCode128PNG(modules []bool, moduleWidth, height int) ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, len(modules)*moduleWidth, height))
+	for x := 0; x < img.Bounds().Dx(); x++ {
+		c := color.Gray{Y: 255}
+		if modules[x/moduleWidth] {
+			c = color.Gray{Y: 0}
+		}
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderCode128SVG buat gambar barcode sebagai SVG, teksnya ikut ditulis di bawah barcode
+func renderCode128SVG(modules []bool, text string, moduleWidth, height int) []byte {
+	width := len(modules) * moduleWidth
+	textHeight := 14
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		width, height+textHeight, width, height+textHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`, width, height+textHeight)
+	for _, r := range barRuns(modules) {
+		fmt.Fprintf(&b, `<rect x="%d" width="%d" height="%d"/>`, r[0]*moduleWidth, r[1]*moduleWidth, height)
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="monospace" font-size="12" text-anchor="middle">%s</text>`,
+		width/2, height+textHeight-2, svgEscape(text))
+	b.WriteString("</svg>")
+	return []byte(b.String())
+}
+
+// barRuns buat gabungin modul hitam yang berurutan jadi satu bar, hasilnya [posisi awal, lebar]
+func barRuns(modules []bool) [][2]int {
+	runs := make([][2]int, 0)
+	for i := 0; i < len(modules); i++ {
+		if !modules[i] {
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		runs = append(runs, [2]int{start, i - start})
+	}
+	return runs
+}
+
+func svgEscape(v string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(v)
+}
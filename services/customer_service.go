@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type CustomerService struct {
+	repo *repositories.CustomerRepository
+}
+
+// NewCustomerService buat bikin instance service baru
+func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService {
+	return &CustomerService{repo: repo}
+}
+
+// GetAll buat ambil semua customers, phone kosong berarti nggak difilter
+func (s *CustomerService) GetAll(phone string) ([]models.Customer, error) {
+	return s.repo.GetAll(normalizePhone(phone))
+}
+
+// GetByID buat ambil customer by ID
+func (s *CustomerService) GetByID(id int) (*models.Customer, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin customer baru
+func (s *CustomerService) Create(customer *models.Customer) error {
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Create(customer)
+}
+
+// Update buat update customer
+func (s *CustomerService) Update(customer *models.Customer) error {
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+	return s.repo.Update(customer)
+}
+
+// Delete buat hapus customer
+func (s *CustomerService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// normalizePhone buat buang spasi, strip, titik dan kurung dari nomor HP, awalan +62/62 diganti 0
+// biar 0812-3456 dan +62 812 3456 dianggap nomor yang sama
+func normalizePhone(phone string) string {
+	phone = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -.()", r) {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+	if strings.HasPrefix(phone, "+62") {
+		phone = "0" + phone[3:]
+	} else if strings.HasPrefix(phone, "62") {
+		phone = "0" + phone[2:]
+	}
+	return phone
+}
+
+func validateCustomer(c *models.Customer) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Email = strings.TrimSpace(c.Email)
+	c.MemberCode = strings.ToUpper(strings.TrimSpace(c.MemberCode))
+	c.Phone = normalizePhone(c.Phone)
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if c.Phone != "" && strings.Trim(c.Phone, "0123456789") != "" {
+		return errors.New("phone can only contain digits")
+	}
+	if c.Email != "" {
+		if _, err := mail.ParseAddress(c.Email); err != nil {
+			return errors.New("email is not valid")
+		}
+	}
+	if len(c.MemberCode) > 32 {
+		return errors.New("member_code must be at most 32 characters")
+	}
+	if strings.ContainsAny(c.MemberCode, " \t\r\n") {
+		return errors.New("member_code cannot contain spaces")
+	}
+	return nil
+}
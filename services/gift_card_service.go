@@ -0,0 +1,126 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+// giftCardAlphabet itu 32 karakter buat code gift card, tanpa 0/O dan 1/I biar nggak salah baca.
+// 16 karakter acak = 80 bit, nggak mungkin ditebak
+const giftCardAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const giftCardCodeLength = 16
+
+type GiftCardService struct {
+	repo *repositories.GiftCardRepository
+}
+
+// NewGiftCardService buat bikin instance service baru
+func NewGiftCardService(repo *repositories.GiftCardRepository) *GiftCardService {
+	return &GiftCardService{repo: repo}
+}
+
+// GetAll buat ambil semua gift card
+func (s *GiftCardService) GetAll() ([]models.GiftCard, error) {
+	return s.repo.GetAll()
+}
+
+// GetByID buat ambil gift card by ID
+func (s *GiftCardService) GetByID(id int) (*models.GiftCard, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetByCode buat cek saldo gift card dari code-nya, spasi, strip dan huruf kecil diabaikan
+func (s *GiftCardService) GetByCode(code string) (*models.GiftCard, error) {
+	code = normalizeGiftCardCode(code)
+	if code == "" {
+		return nil, errors.New("code is required")
+	}
+	return s.repo.GetByCode(code)
+}
+
+// Create buat nerbitin gift card baru dengan code acak, code yang dikirim client diabaikan
+func (s *GiftCardService) Create(giftCard *models.GiftCard) error {
+	giftCard.Note = strings.TrimSpace(giftCard.Note)
+	if giftCard.InitialAmount <= 0 {
+		return errors.New("initial_amount must be greater than 0")
+	}
+	if giftCard.ExpiresAt != nil && !giftCard.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+
+	code, err := generateGiftCardCode()
+	if err != nil {
+		return err
+	}
+	giftCard.Code = code
+	return s.repo.Create(giftCard)
+}
+
+// GetEntries buat ambil ledger satu gift card sekalian saldonya
+func (s *GiftCardService) GetEntries(giftCardID, page, limit int) (*models.GiftCardEntryList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	balance, entries, total, err := s.repo.GetEntries(giftCardID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GiftCardEntryList{
+		GiftCardID: giftCardID,
+		Balance:    balance,
+		Data:       entries,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+	}, nil
+}
+
+// generateGiftCardCode buat bikin code gift card acak dari crypto/rand, formatnya XXXX-XXXX-XXXX-XXXX.
+// 256 habis dibagi 32 jadi ambil 5 bit bawah tiap byte nggak bikin karakter tertentu lebih sering keluar
+func generateGiftCardCode() (string, error) {
+	buf := make([]byte, giftCardCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = giftCardAlphabet[b%byte(len(giftCardAlphabet))]
+	}
+	return formatGiftCardCode(string(buf)), nil
+}
+
+// normalizeGiftCardCode buat ngerapiin code yang diketik kasir: huruf besar, tanpa spasi/strip,
+// terus dikelompokkan lagi per 4 karakter kayak waktu diterbitkan
+func normalizeGiftCardCode(code string) string {
+	code = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+	if len(code) != giftCardCodeLength {
+		return code
+	}
+	return formatGiftCardCode(code)
+}
+
+func formatGiftCardCode(code string) string {
+	groups := make([]string, 0, len(code)/4)
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:i+4])
+	}
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+// Isi barcode Code128 di label: ID produk atau SKU-nya
+const (
+	LabelBarcodeSourceID  = "id"
+	LabelBarcodeSourceSKU = "sku"
+)
+
+// Format gambar barcode yang didukung
+const (
+	BarcodeFormatPNG = "png"
+	BarcodeFormatSVG = "svg"
+)
+
+// LabelConfig itu konfigurasi label rak. BarcodeSource kosong berarti pakai ID produk,
+// kalau sku tapi produknya belum punya SKU tetep pakai ID
+type LabelConfig struct {
+	BarcodeSource string
+}
+
+type LabelService struct {
+	repo   *repositories.ProductRepository
+	config LabelConfig
+}
+
+// NewLabelService buat bikin instance service baru
+func NewLabelService(repo *repositories.ProductRepository, config LabelConfig) (*LabelService, error) {
+	switch config.BarcodeSource {
+	case "":
+		config.BarcodeSource = LabelBarcodeSourceID
+	case LabelBarcodeSourceID, LabelBarcodeSourceSKU:
+	default:
+		return nil, errors.New("label barcode source must be id or sku")
+	}
+	return &LabelService{repo: repo, config: config}, nil
+}
+
+// barcodeText buat nentuin teks yang di-encode ke barcode Code128 produk
+func (s *LabelService) barcodeText(p *models.Product) string {
+	if s.config.BarcodeSource == LabelBarcodeSourceSKU && p.SKU != "" {
+		return p.SKU
+	}
+	return strconv.Itoa(p.ID)
+}
+
+// RenderBarcode buat bikin gambar barcode Code128 satu produk dalam format png atau svg
+func (s *LabelService) RenderBarcode(productID int, format string) ([]byte, error) {
+	product, err := s.repo.GetByID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	text := s.barcodeText(product)
+	modules, err := encodeCode128(text)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case BarcodeFormatPNG, "":
+		return renderCode128PNG(modules, 2, 80)
+	case BarcodeFormatSVG:
+		return renderCode128SVG(modules, text, 2, 80), nil
+	}
+	return nil, errors.New("format must be png or svg")
+}
+
+// RenderLabelSheet buat bikin PDF A4 berisi label rak (nama, harga, barcode) buat produk yang dipilih
+// atau semua produk dalam satu kategori. Urutan label ikut urutan productIDs
+func (s *LabelService) RenderLabelSheet(productIDs []int, categoryID int) ([]byte, error) {
+	var products []models.Product
+	var err error
+	if categoryID != 0 {
+		products, err = s.repo.GetByCategoryID(categoryID)
+	} else {
+		products, err = s.repo.GetByIDs(productIDs)
+		products = orderProducts(products, productIDs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return nil, errors.New("no products found for labels")
+	}
+
+	pages := make([]string, 0)
+	var page strings.Builder
+	for i := range products {
+		slot := i % labelsPerPage
+		if slot == 0 && i > 0 {
+			pages = append(pages, page.String())
+			page.Reset()
+		}
+
+		x := labelMargin + float64(slot%labelColumns)*labelWidth
+		y := pdfPageHeight - labelMargin - float64(slot/labelColumns+1)*labelHeight
+		if err := s.writeLabel(&page, &products[i], x, y); err != nil {
+			return nil, err
+		}
+	}
+	pages = append(pages, page.String())
+
+	return buildPDF(pages), nil
+}
+
+// orderProducts buat ngurutin products sesuai urutan ID yang diminta, ID yang dobel dapet label dobel
+func orderProducts(products []models.Product, ids []int) []models.Product {
+	byID := make(map[int]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	ordered := make([]models.Product, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			ordered = append(ordered, p)
+		}
+	}
+	return ordered
+}
+
+// Ukuran halaman dan label dalam point PDF (1/72 inch). A4, 3 kolom x 8 baris
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	labelMargin   = 20.0
+	labelColumns  = 3
+	labelRows     = 8
+	labelsPerPage = labelColumns * labelRows
+	labelWidth    = (pdfPageWidth - 2*labelMargin) / labelColumns
+	labelHeight   = (pdfPageHeight - 2*labelMargin) / labelRows
+)
+
+// writeLabel buat nulis perintah gambar satu label ke content stream halaman PDF.
+// (x, y) itu pojok kiri bawah label
+func (s *LabelService) writeLabel(page *strings.Builder, p *models.Product, x, y float64) error {
+	text := s.barcodeText(p)
+	modules, err := encodeCode128(text)
+	if err != nil {
+		return fmt.Errorf("product %d: %w", p.ID, err)
+	}
+
+	// Garis potong tipis warna abu-abu
+	fmt.Fprintf(page, "0.7 G 0.5 w %.2f %.2f %.2f %.2f re S\n", x, y, labelWidth, labelHeight)
+
+	padding := 8.0
+	fmt.Fprintf(page, "0 g BT /F1 9 Tf %.2f %.2f Td (%s) Tj ET\n", x+padding, y+labelHeight-16, pdfText(truncateRunes(p.Name, 34)))
+	fmt.Fprintf(page, "BT /F2 16 Tf %.2f %.2f Td (%s) Tj ET\n", x+padding, y+labelHeight-36, pdfText("Rp "+formatRupiah(p.Price)))
+
+	// Barcode dikecilin kalau kepanjangan buat lebar label, maksimal 1pt per modul
+	moduleWidth := min(1.0, (labelWidth-2*padding)/float64(len(modules)))
+	for _, r := range barRuns(modules) {
+		fmt.Fprintf(page, "%.3f %.2f %.3f 30 re f\n", x+padding+float64(r[0])*moduleWidth, y+16, float64(r[1])*moduleWidth)
+	}
+	fmt.Fprintf(page, "BT /F1 7 Tf %.2f %.2f Td (%s) Tj ET\n", x+padding+code128QuietZone*moduleWidth, y+7, pdfText(text))
+
+	return nil
+}
+
+// buildPDF buat nyusun file PDF dari content stream tiap halaman. Font-nya pakai
+// Helvetica bawaan PDF reader jadi nggak perlu embed font
+func buildPDF(pages []string) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"", // Pages, diisi setelah nomor object halaman ketahuan
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	kids := make([]string, 0, len(pages))
+	for _, content := range pages {
+		pageObj := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfPageWidth, pdfPageHeight, pageObj+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return []byte(b.String())
+}
+
+// pdfText buat escape teks buat string literal PDF. Karakter non-ASCII jadi '?' karena
+// font standar PDF nggak bisa nampilin UTF-8
+func pdfText(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(toPrinterASCII(v))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+// LoyaltyConfig itu konfigurasi program poin member.
+// EarnAmount: tiap belanja segini rupiah (total yang dibayar) dapet 1 poin, 0 berarti nggak dapet poin.
+// PointValue: nilai 1 poin dalam rupiah waktu ditukar jadi diskon, 0 berarti poin nggak bisa ditukar.
+// ExpiryDays: masa berlaku poin sejak didapet, 0 berarti nggak kadaluarsa.
+// ExpiryCheckInterval: jeda job pengecekan poin kadaluarsa dalam detik (default 3600)
+type LoyaltyConfig struct {
+	EarnAmount          int
+	PointValue          int
+	ExpiryDays          int
+	ExpiryCheckInterval int
+}
+
+// LoyaltyService itu program poin member: ngitung poin yang didapet dan diskon tukar poin waktu checkout,
+// plus job background yang ngangusin poin kadaluarsa
+type LoyaltyService struct {
+	repo     *repositories.LoyaltyRepository
+	config   LoyaltyConfig
+	interval time.Duration
+}
+
+// NewLoyaltyService buat bikin instance service baru
+func NewLoyaltyService(repo *repositories.LoyaltyRepository, config LoyaltyConfig) (*LoyaltyService, error) {
+	if config.EarnAmount < 0 || config.PointValue < 0 || config.ExpiryDays < 0 {
+		return nil, errors.New("loyalty earn amount, point value and expiry days cannot be negative")
+	}
+	interval := config.ExpiryCheckInterval
+	if interval <= 0 {
+		interval = 3600
+	}
+	return &LoyaltyService{repo: repo, config: config, interval: time.Duration(interval) * time.Second}, nil
+}
+
+// ExpiryDays buat ambil masa berlaku poin, dipakai repository waktu nyatet poin masuk
+func (s *LoyaltyService) ExpiryDays() int {
+	return s.config.ExpiryDays
+}
+
+// GetEntries buat ambil ledger poin satu customer sekalian saldonya
+func (s *LoyaltyService) GetEntries(customerID, page, limit int) (*models.PointEntryList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	points, entries, total, err := s.repo.GetEntries(customerID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PointEntryList{
+		CustomerID: customerID,
+		Points:     points,
+		Data:       entries,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+	}, nil
+}
+
+// RedeemPoints buat ngubah poin yang ditukar jadi diskon transaksi, dipanggil setelah promo dan sebelum pajak.
+// Diskonnya dibagi ke tiap baris kayak diskon promo transaksi, jadi refund sebagian tetap proporsional
+func (s *LoyaltyService) RedeemPoints(t *models.Transaction) error {
+	if t.PointsRedeemed == 0 {
+		return nil
+	}
+	if s.config.PointValue == 0 {
+		return errors.New("points redemption is disabled")
+	}
+
+	subtotal := 0
+	for _, d := range t.Details {
+		subtotal += d.Subtotal
+	}
+	discount := t.PointsRedeemed * s.config.PointValue
+	if discount > subtotal {
+		return fmt.Errorf("redeeming %d points (Rp %d) exceeds the amount to pay after discounts (Rp %d)", t.PointsRedeemed, discount, subtotal)
+	}
+
+	allocateDiscount(t.Details, subtotal, discount)
+	t.PointsDiscount = discount
+	t.DiscountAmount += discount
+	t.TotalAmount = t.GrossAmount - t.DiscountAmount
+	return nil
+}
+
+// EarnPoints buat ngitung poin yang didapet member dari total yang dibayar, dipanggil setelah pajak
+func (s *LoyaltyService) EarnPoints(t *models.Transaction) {
+	t.PointsEarned = 0
+	if t.CustomerID != 0 && s.config.EarnAmount > 0 {
+		t.PointsEarned = t.TotalAmount / s.config.EarnAmount
+	}
+}
+
+// Run buat jalanin job poin kadaluarsa sampai ctx selesai, panggil pakai goroutine.
+// Checkout yang nukar poin juga ngangusin poin kadaluarsa customer itu duluan, jadi job ini
+// cuma biar saldo dan ledger customer lain nggak ketinggalan
+func (s *LoyaltyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		points, customers, err := s.repo.ExpirePoints()
+		if err != nil {
+			log.Println("loyalty: gagal ngangusin poin kadaluarsa:", err)
+		} else if points > 0 {
+			log.Printf("loyalty: %d poin kadaluarsa dari %d customer", points, customers)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
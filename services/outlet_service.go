@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type OutletService struct {
+	repo *repositories.OutletRepository
+}
+
+// NewOutletService buat bikin instance service baru
+func NewOutletService(repo *repositories.OutletRepository) *OutletService {
+	return &OutletService{repo: repo}
+}
+
+// GetAll buat ambil semua outlets
+func (s *OutletService) GetAll() ([]models.Outlet, error) {
+	return s.repo.GetAll()
+}
+
+// GetByID buat ambil outlet by ID
+func (s *OutletService) GetByID(id int) (*models.Outlet, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin outlet baru
+func (s *OutletService) Create(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Create(outlet)
+}
+
+// Update buat update outlet
+func (s *OutletService) Update(outlet *models.Outlet) error {
+	if err := validateOutlet(outlet); err != nil {
+		return err
+	}
+	return s.repo.Update(outlet)
+}
+
+// Delete buat hapus outlet
+func (s *OutletService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// GetStock buat ambil stock semua produk di satu outlet
+func (s *OutletService) GetStock(id int) ([]models.OutletStock, error) {
+	return s.repo.GetStock(id)
+}
+
+func validateOutlet(o *models.Outlet) error {
+	o.Name = strings.TrimSpace(o.Name)
+	o.Address = strings.TrimSpace(o.Address)
+	if o.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
 )
@@ -19,18 +22,83 @@ func (s *ProductService) GetAll(name string) ([]models.Product, error) {
 	return s.repo.GetAll(name)
 }
 
-// GetByID buat ambil product by ID
+// GetByID buat ambil product by ID, kalau produk induk varian-variannya ikut diisi
 func (s *ProductService) GetByID(id int) (*models.Product, error) {
-	return s.repo.GetByID(id)
+	product, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.ParentID == 0 && len(product.VariantAttributes) > 0 {
+		product.Variants, err = s.repo.GetVariants(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return product, nil
 }
 
-// Create buat bikin product baru
+// GetVariants buat ambil semua varian satu produk induk
+func (s *ProductService) GetVariants(parentID int) ([]models.Product, error) {
+	if _, err := s.repo.GetByID(parentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetVariants(parentID)
+}
+
+// GetByBarcode buat ambil product dari hasil scan barcode EAN-13 atau UPC-A
+func (s *ProductService) GetByBarcode(code string) (*models.Product, error) {
+	barcode, err := normalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByBarcode(barcode)
+}
+
+// Create buat bikin product baru. Varian dibikin lewat CreateVariant, bukan di sini
 func (s *ProductService) Create(product *models.Product) error {
+	product.ParentID, product.VariantName, product.Attributes, product.PriceOverride = 0, "", nil, false
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+	if product.Stock < 0 {
+		return errors.New("stock cannot be negative")
+	}
+
+	// Stock dan HPP paket dihitung dari komponennya, jadi nggak diisi di paketnya sendiri
+	if product.IsBundle {
+		if product.Stock != 0 {
+			return errors.New("bundle stock is tracked on its components, stock must be 0")
+		}
+		if len(product.VariantAttributes) > 0 {
+			return errors.New("bundle cannot have variants")
+		}
+		if product.TrackLots {
+			return errors.New("bundle cannot track lots, track them on its components")
+		}
+		product.CostPrice, product.MinStock, product.ReorderQuantity = 0, 0, 0
+	} else if len(product.Components) > 0 {
+		return errors.New("components can only be set on a bundle product")
+	}
 	return s.repo.Create(product)
 }
 
+// CreateVariant buat bikin varian baru di bawah produk induk
+func (s *ProductService) CreateVariant(parentID int, variant *models.Product) error {
+	if err := validateProduct(variant); err != nil {
+		return err
+	}
+	if variant.PriceOverride && variant.Price <= 0 {
+		return errors.New("price must be greater than 0 when price_override is true")
+	}
+	return s.repo.CreateVariant(parentID, variant)
+}
+
 // Update buat update product
 func (s *ProductService) Update(product *models.Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
 	return s.repo.Update(product)
 }
 
@@ -38,3 +106,96 @@ func (s *ProductService) Update(product *models.Product) error {
 func (s *ProductService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+// validateProduct buat ngecek SKU, barcode, batas stock, dan satuan. Barcode dinormalisasi jadi EAN-13
+func validateProduct(p *models.Product) error {
+	p.SKU = strings.TrimSpace(p.SKU)
+	if len(p.SKU) > 64 {
+		return errors.New("sku must be at most 64 characters")
+	}
+	if strings.ContainsAny(p.SKU, " \t\r\n") {
+		return errors.New("sku cannot contain spaces")
+	}
+
+	barcodes, err := normalizeBarcodes(p.Barcodes)
+	if err != nil {
+		return err
+	}
+	p.Barcodes = barcodes
+
+	if p.MinStock < 0 || p.ReorderQuantity < 0 {
+		return errors.New("min_stock and reorder_quantity cannot be negative")
+	}
+
+	p.BaseUnit = strings.TrimSpace(p.BaseUnit)
+	if p.BaseUnit == "" {
+		p.BaseUnit = "pcs"
+	}
+	if len(p.BaseUnit) > 20 {
+		return errors.New("base_unit must be at most 20 characters")
+	}
+	if p.Units != nil {
+		seen := make(map[string]bool)
+		for i := range p.Units {
+			u := &p.Units[i]
+			u.Name = strings.TrimSpace(u.Name)
+			if u.Name == "" || len(u.Name) > 20 {
+				return errors.New("unit name is required and must be at most 20 characters")
+			}
+			if seen[u.Name] {
+				return errors.New("duplicate unit " + u.Name)
+			}
+			seen[u.Name] = true
+			if u.Factor <= 0 {
+				return errors.New("factor for unit " + u.Name + " must be greater than 0")
+			}
+			if u.Price < 0 {
+				return errors.New("price for unit " + u.Name + " cannot be negative")
+			}
+		}
+	}
+
+	p.Variants = nil
+	if p.VariantAttributes != nil {
+		names := make([]string, 0, len(p.VariantAttributes))
+		seen := make(map[string]bool)
+		for _, name := range p.VariantAttributes {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return errors.New("variant attribute name cannot be empty")
+			}
+			if seen[name] {
+				return errors.New("duplicate variant attribute " + name)
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+		p.VariantAttributes = names
+	}
+
+	if p.Components != nil {
+		components := make([]models.BundleComponent, 0, len(p.Components))
+		index := make(map[int]int)
+		for _, c := range p.Components {
+			if c.ProductID <= 0 || c.Quantity <= 0 {
+				return errors.New("bundle component needs product_id and quantity greater than 0")
+			}
+			if i, ok := index[c.ProductID]; ok {
+				components[i].Quantity += c.Quantity
+				continue
+			}
+			index[c.ProductID] = len(components)
+			components = append(components, models.BundleComponent{ProductID: c.ProductID, Quantity: c.Quantity})
+		}
+		p.Components = components
+	}
+
+	if p.Attributes != nil {
+		attributes := make(map[string]string, len(p.Attributes))
+		for name, value := range p.Attributes {
+			attributes[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+		p.Attributes = attributes
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type PromotionService struct {
+	repo *repositories.PromotionRepository
+}
+
+// NewPromotionService buat bikin instance service baru
+func NewPromotionService(repo *repositories.PromotionRepository) *PromotionService {
+	return &PromotionService{repo: repo}
+}
+
+// GetAll buat ambil semua promotions
+func (s *PromotionService) GetAll() ([]models.Promotion, error) {
+	return s.repo.GetAll()
+}
+
+// GetByID buat ambil promotion by ID
+func (s *PromotionService) GetByID(id int) (*models.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin promotion baru
+func (s *PromotionService) Create(promotion *models.Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+	return s.repo.Create(promotion)
+}
+
+// Update buat update promotion
+func (s *PromotionService) Update(promotion *models.Promotion) error {
+	if err := validatePromotion(promotion); err != nil {
+		return err
+	}
+	return s.repo.Update(promotion)
+}
+
+// Delete buat hapus promotion
+func (s *PromotionService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validatePromotion buat ngecek kombinasi type, scope, dan value promo masuk akal
+func validatePromotion(p *models.Promotion) error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+
+	switch p.Scope {
+	case models.PromotionScopeProduct:
+		if p.ProductID == 0 {
+			return errors.New("product_id is required for product scope")
+		}
+		p.CategoryID = 0
+	case models.PromotionScopeCategory:
+		if p.CategoryID == 0 {
+			return errors.New("category_id is required for category scope")
+		}
+		p.ProductID = 0
+	case models.PromotionScopeTransaction:
+		p.ProductID, p.CategoryID = 0, 0
+	default:
+		return errors.New("scope must be product, category or transaction")
+	}
+
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return errors.New("value for percentage promotion must be between 1 and 100")
+		}
+	case models.PromotionTypeFixed:
+		if p.Value < 1 {
+			return errors.New("value for fixed promotion must be greater than 0")
+		}
+	case models.PromotionTypeBuyXGetY:
+		if p.Scope == models.PromotionScopeTransaction {
+			return errors.New("buy_x_get_y promotion must have product or category scope")
+		}
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return errors.New("buy_quantity and get_quantity must be greater than 0")
+		}
+		p.Value = 0
+	default:
+		return errors.New("type must be percentage, fixed or buy_x_get_y")
+	}
+
+	if p.MinSpend < 0 || p.MaxDiscount < 0 {
+		return errors.New("min_spend and max_discount cannot be negative")
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && p.EndsAt.Before(*p.StartsAt) {
+		return errors.New("ends_at cannot be before starts_at")
+	}
+
+	return nil
+}
+
+// ApplyPromotions buat ngitung diskon checkout dari promo yang lagi aktif.
+// Tiap baris dapet satu promo item terbaik (product/category), lalu satu promo transaksi terbaik
+// dihitung dari subtotal setelah diskon item dan dibagi rata proporsional ke tiap baris
+func (s *PromotionService) ApplyPromotions(t *models.Transaction) error {
+	promotions, err := s.repo.GetActive()
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[int]*models.AppliedPromotion)
+	order := make([]int, 0)
+	apply := func(p models.Promotion, amount int) {
+		if _, ok := applied[p.ID]; !ok {
+			applied[p.ID] = &models.AppliedPromotion{PromotionID: p.ID, Name: p.Name, Scope: p.Scope}
+			order = append(order, p.ID)
+		}
+		applied[p.ID].Amount += amount
+	}
+
+	subtotal := 0
+	for i := range t.Details {
+		d := &t.Details[i]
+
+		var best *models.Promotion
+		bestAmount := 0
+		for j := range promotions {
+			p := &promotions[j]
+			if t.GrossAmount < p.MinSpend {
+				continue
+			}
+			if amount := lineDiscount(*p, *d); amount > bestAmount {
+				best, bestAmount = p, amount
+			}
+		}
+
+		if best != nil {
+			d.PromotionID = best.ID
+			d.DiscountAmount = bestAmount
+			d.Subtotal = d.GrossSubtotal - bestAmount
+			apply(*best, bestAmount)
+		}
+		subtotal += d.Subtotal
+	}
+
+	var bestTransaction *models.Promotion
+	transactionAmount := 0
+	for j := range promotions {
+		p := &promotions[j]
+		if p.Scope != models.PromotionScopeTransaction || t.GrossAmount < p.MinSpend {
+			continue
+		}
+		if amount := discountAmount(*p, subtotal); amount > transactionAmount {
+			bestTransaction, transactionAmount = p, amount
+		}
+	}
+
+	if bestTransaction != nil {
+		allocateDiscount(t.Details, subtotal, transactionAmount)
+		apply(*bestTransaction, transactionAmount)
+	}
+
+	t.DiscountAmount = 0
+	t.Promotions = make([]models.AppliedPromotion, 0, len(order))
+	for _, id := range order {
+		t.DiscountAmount += applied[id].Amount
+		t.Promotions = append(t.Promotions, *applied[id])
+	}
+	t.TotalAmount = t.GrossAmount - t.DiscountAmount
+
+	return nil
+}
+
+// lineDiscount buat ngitung diskon promo item ke satu baris transaksi, 0 kalau promonya nggak cocok.
+// Fixed dan buy_x_get_y dihitung per satuan yang dijual (UnitQuantity dengan harga UnitPrice), bukan per
+// satuan dasar, biar promo per box nggak jadi per pcs dan barang timbangan nggak dihitung per gram
+func lineDiscount(p models.Promotion, d models.TransactionDetail) int {
+	switch p.Scope {
+	case models.PromotionScopeProduct:
+		if p.ProductID != d.ProductID {
+			return 0
+		}
+	case models.PromotionScopeCategory:
+		if p.CategoryID == 0 || p.CategoryID != d.CategoryID {
+			return 0
+		}
+	default:
+		return 0
+	}
+
+	if p.Type == models.PromotionTypeBuyXGetY {
+		// Cuma satuan yang bulat yang dihitung, 2.5 kg itu 2 satuan
+		freeQty := int(math.Floor(d.UnitQuantity)) / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+		return capDiscount(p, freeQty*d.UnitPrice, d.GrossSubtotal)
+	}
+	if p.Type == models.PromotionTypeFixed {
+		return capDiscount(p, int(math.Round(float64(p.Value)*d.UnitQuantity)), d.GrossSubtotal)
+	}
+	return discountAmount(p, d.GrossSubtotal)
+}
+
+// discountAmount buat ngitung diskon percentage/fixed dari sebuah nilai belanja
+func discountAmount(p models.Promotion, amount int) int {
+	switch p.Type {
+	case models.PromotionTypePercentage:
+		return capDiscount(p, amount*p.Value/100, amount)
+	case models.PromotionTypeFixed:
+		return capDiscount(p, p.Value, amount)
+	}
+	return 0
+}
+
+// capDiscount buat batasin diskon ke max_discount dan ke nilai belanjanya
+func capDiscount(p models.Promotion, discount, amount int) int {
+	if p.MaxDiscount > 0 {
+		discount = min(discount, p.MaxDiscount)
+	}
+	return max(0, min(discount, amount))
+}
+
+// allocateDiscount buat bagi diskon transaksi ke tiap baris sesuai proporsi subtotal,
+// sisa pembulatan dikasih ke baris yang masih punya subtotal
+func allocateDiscount(details []models.TransactionDetail, subtotal, discount int) {
+	if subtotal == 0 {
+		return
+	}
+
+	remaining := discount
+	for i := range details {
+		share := discount * details[i].Subtotal / subtotal
+		details[i].DiscountAmount += share
+		details[i].Subtotal -= share
+		remaining -= share
+	}
+	for i := range details {
+		if remaining == 0 {
+			break
+		}
+		share := min(remaining, details[i].Subtotal)
+		details[i].DiscountAmount += share
+		details[i].Subtotal -= share
+		remaining -= share
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type PurchaseOrderService struct {
+	repo         *repositories.PurchaseOrderRepository
+	supplierRepo *repositories.SupplierRepository
+}
+
+// NewPurchaseOrderService buat bikin instance service baru
+func NewPurchaseOrderService(repo *repositories.PurchaseOrderRepository, supplierRepo *repositories.SupplierRepository) *PurchaseOrderService {
+	return &PurchaseOrderService{repo: repo, supplierRepo: supplierRepo}
+}
+
+// GetAll buat ambil semua purchase order, status kosong berarti semua status
+func (s *PurchaseOrderService) GetAll(status string) ([]models.PurchaseOrder, error) {
+	return s.repo.GetAll(status)
+}
+
+// GetByID buat ambil purchase order by ID
+func (s *PurchaseOrderService) GetByID(id int) (*models.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin purchase order baru (draft)
+func (s *PurchaseOrderService) Create(po *models.PurchaseOrder) error {
+	if err := s.validatePurchaseOrder(po); err != nil {
+		return err
+	}
+	if err := s.repo.Create(po); err != nil {
+		return err
+	}
+	return s.reload(po)
+}
+
+// Update buat update purchase order yang masih draft
+func (s *PurchaseOrderService) Update(po *models.PurchaseOrder) error {
+	if err := s.validatePurchaseOrder(po); err != nil {
+		return err
+	}
+	if err := s.repo.Update(po); err != nil {
+		return err
+	}
+	return s.reload(po)
+}
+
+// reload buat isi ulang nama supplier dan nama produk setelah disimpan
+func (s *PurchaseOrderService) reload(po *models.PurchaseOrder) error {
+	saved, err := s.repo.GetByID(po.ID)
+	if err != nil {
+		return err
+	}
+	*po = *saved
+	return nil
+}
+
+// Send buat nandain purchase order udah dikirim ke supplier
+func (s *PurchaseOrderService) Send(id int) (*models.PurchaseOrder, error) {
+	err := s.repo.UpdateStatus(id, []string{models.PurchaseOrderStatusDraft}, models.PurchaseOrderStatusSent)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Cancel buat batalin purchase order yang belum ada barang diterima
+func (s *PurchaseOrderService) Cancel(id int) (*models.PurchaseOrder, error) {
+	err := s.repo.UpdateStatus(id,
+		[]string{models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent}, models.PurchaseOrderStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Receive buat nyatet penerimaan barang, boleh sebagian
+func (s *PurchaseOrderService) Receive(id int, req models.ReceiveRequest) (*models.GoodsReceipt, error) {
+	req.ReceivedBy = strings.TrimSpace(req.ReceivedBy)
+	req.Note = strings.TrimSpace(req.Note)
+	if req.ReceivedBy == "" {
+		return nil, errors.New("received_by is required")
+	}
+	if len(req.Items) == 0 {
+		return nil, errors.New("items cannot be empty")
+	}
+	for i := range req.Items {
+		item := &req.Items[i]
+		item.Unit = strings.TrimSpace(item.Unit)
+		if err := normalizeLotInput(&item.BatchNumber, &item.ExpiryDate); err != nil {
+			return nil, err
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity for purchase order item id %d must be greater than 0", item.PurchaseOrderItemID)
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("unit_cost for purchase order item id %d cannot be negative", item.PurchaseOrderItemID)
+		}
+	}
+
+	return s.repo.Receive(id, req)
+}
+
+// validatePurchaseOrder buat ngecek supplier ada dan item-nya valid, satu produk cuma boleh satu baris
+func (s *PurchaseOrderService) validatePurchaseOrder(po *models.PurchaseOrder) error {
+	po.Note = strings.TrimSpace(po.Note)
+	if po.SupplierID == 0 {
+		return errors.New("supplier_id is required")
+	}
+	if _, err := s.supplierRepo.GetByID(po.SupplierID); err != nil {
+		return err
+	}
+	if len(po.Items) == 0 {
+		return errors.New("items cannot be empty")
+	}
+
+	seen := make(map[int]bool)
+	for _, item := range po.Items {
+		if item.ProductID == 0 {
+			return errors.New("product_id is required")
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("product id %d is listed more than once", item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity for product id %d must be greater than 0", item.ProductID)
+		}
+		if item.UnitCost < 0 {
+			return fmt.Errorf("unit_cost for product id %d cannot be negative", item.ProductID)
+		}
+	}
+
+	return nil
+}
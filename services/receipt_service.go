@@ -0,0 +1,375 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	htmltemplate "html/template"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+// Format struk yang didukung
+const (
+	ReceiptFormatText   = "text"
+	ReceiptFormatHTML   = "html"
+	ReceiptFormatESCPOS = "escpos"
+)
+
+// ReceiptConfig itu konfigurasi header/footer toko dan template struk.
+// TextTemplatePath dan HTMLTemplatePath opsional, kalau kosong pakai template bawaan
+type ReceiptConfig struct {
+	StoreName        string
+	StoreAddress     string
+	StorePhone       string
+	Footer           string
+	Width            int
+	TextTemplatePath string
+	HTMLTemplatePath string
+}
+
+// ReceiptData itu data yang dikirim ke template struk
+type ReceiptData struct {
+	Store       ReceiptConfig
+	Transaction *models.Transaction
+}
+
+type ReceiptService struct {
+	transactionService *TransactionService
+	config             ReceiptConfig
+	textTemplate       string
+	htmlTemplate       *htmltemplate.Template
+}
+
+// NewReceiptService buat bikin instance service baru, template dibaca dan dicek sekali di awal
+func NewReceiptService(transactionService *TransactionService, config ReceiptConfig) (*ReceiptService, error) {
+	if config.Width <= 0 {
+		config.Width = 32
+	}
+	if config.StoreName == "" {
+		config.StoreName = "Kasir"
+	}
+
+	textSource := defaultReceiptTextTemplate
+	if config.TextTemplatePath != "" {
+		b, err := os.ReadFile(config.TextTemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		textSource = string(b)
+	}
+
+	htmlSource := defaultReceiptHTMLTemplate
+	if config.HTMLTemplatePath != "" {
+		b, err := os.ReadFile(config.HTMLTemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		htmlSource = string(b)
+	}
+
+	s := &ReceiptService{transactionService: transactionService, config: config, textTemplate: textSource}
+
+	// Parse sekali di sini biar template yang rusak ketahuan waktu start, bukan waktu cetak
+	if _, err := s.parseText(false); err != nil {
+		return nil, err
+	}
+	htmlTmpl, err := htmltemplate.New("receipt").Funcs(htmltemplate.FuncMap{
+		"quantity":     formatQuantity,
+		"rupiah":       formatRupiah,
+		"date":         formatReceiptDate,
+		"paymentLabel": paymentLabel,
+	}).Parse(htmlSource)
+	if err != nil {
+		return nil, err
+	}
+	s.htmlTemplate = htmlTmpl
+
+	return s, nil
+}
+
+// Render buat bikin struk transaksi dalam format text, html, atau escpos (raw bytes buat printer thermal)
+func (s *ReceiptService) Render(transactionID int, format string) ([]byte, error) {
+	transaction, err := s.transactionService.GetByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	data := ReceiptData{Store: s.config, Transaction: transaction}
+
+	var buf bytes.Buffer
+	switch format {
+	case ReceiptFormatText, "":
+		tmpl, err := s.parseText(false)
+		if err != nil {
+			return nil, err
+		}
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+	case ReceiptFormatHTML:
+		if err := s.htmlTemplate.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+	case ReceiptFormatESCPOS:
+		tmpl, err := s.parseText(true)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(escposInit)
+		var text bytes.Buffer
+		if err := tmpl.Execute(&text, data); err != nil {
+			return nil, err
+		}
+		buf.WriteString(toPrinterASCII(text.String()))
+		buf.WriteString(escposFeedAndCut)
+	default:
+		return nil, errors.New("format must be text, html or escpos")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Perintah ESC/POS yang dipakai
+const (
+	escposInit       = "\x1b@"              // ESC @: reset printer
+	escposBoldOn     = "\x1bE\x01"          // ESC E 1
+	escposBoldOff    = "\x1bE\x00"          // ESC E 0
+	escposFeedAndCut = "\x1bd\x04\x1dV\x01" // ESC d 4: feed 4 baris, GS V 1: partial cut
+)
+
+// parseText buat parse template text. Template yang sama dipakai buat escpos, bedanya cuma
+// fungsi bold yang ngeluarin perintah ESC/POS
+func (s *ReceiptService) parseText(escpos bool) (*template.Template, error) {
+	width := s.config.Width
+	bold := func(v string) string { return v }
+	if escpos {
+		bold = func(v string) string { return escposBoldOn + v + escposBoldOff }
+	}
+
+	return template.New("receipt").Funcs(template.FuncMap{
+		"bold": bold,
+		"center": func(v string) string {
+			v = truncateRunes(v, width)
+			return strings.Repeat(" ", (width-utf8.RuneCountInString(v))/2) + v
+		},
+		"line": func(char string) string { return strings.Repeat(char, width) },
+		"row": func(left, right string) string {
+			left = truncateRunes(left, width-utf8.RuneCountInString(right)-1)
+			gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+			return left + strings.Repeat(" ", max(gap, 1)) + right
+		},
+		"wrap":         func(v string) string { return truncateRunes(v, width) },
+		"quantity":     formatQuantity,
+		"rupiah":       formatRupiah,
+		"date":         formatReceiptDate,
+		"paymentLabel": paymentLabel,
+	}).Parse(s.textTemplate)
+}
+
+// formatRupiah buat format angka pakai pemisah ribuan titik, misalnya 12500 jadi 12.500
+func formatRupiah(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	digits := strconv.Itoa(amount)
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String()
+}
+
+// formatQuantity buat nampilin jumlah item sesuai satuan jualnya, misalnya "2 box" atau "0.25 kg".
+// Transaksi lama yang belum nyimpen satuan cuma nampilin quantity-nya
+func formatQuantity(d models.TransactionDetail) string {
+	if d.Unit == "" {
+		return strconv.Itoa(d.Quantity)
+	}
+	return strconv.FormatFloat(d.UnitQuantity, 'f', -1, 64) + " " + d.Unit
+}
+
+func formatReceiptDate(t time.Time) string {
+	return t.Format("02/01/2006 15:04")
+}
+
+func paymentLabel(method string) string {
+	switch method {
+	case models.PaymentMethodCash:
+		return "Tunai"
+	case models.PaymentMethodDebitCard:
+		return "Kartu Debit"
+	case models.PaymentMethodQRIS:
+		return "QRIS"
+	case models.PaymentMethodEWallet:
+		return "E-Wallet"
+	case models.PaymentMethodGiftCard:
+		return "Gift Card"
+	}
+	return method
+}
+
+func truncateRunes(v string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(v) <= n {
+		return v
+	}
+	return string([]rune(v)[:n])
+}
+
+// toPrinterASCII buat ganti karakter non-ASCII jadi '?' karena printer thermal standarnya cuma ngerti ASCII
+func toPrinterASCII(v string) string {
+	var b strings.Builder
+	for _, c := range v {
+		if c < 0x80 {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+const defaultReceiptTextTemplate = `{{bold (center .Store.StoreName)}}
+{{- if .Store.StoreAddress}}
+{{center .Store.StoreAddress}}
+{{- end}}
+{{- if .Store.StorePhone}}
+{{center .Store.StorePhone}}
+{{- end}}
+{{line "="}}
+{{row (printf "No. %d" .Transaction.ID) (date .Transaction.CreatedAt)}}
+{{- if eq .Transaction.Status "voided"}}
+{{bold (center "*** VOID ***")}}
+{{- end}}
+{{line "-"}}
+{{- range .Transaction.Details}}
+{{wrap .ProductName}}
+{{row (printf "  %s x %s" (quantity .) (rupiah .UnitPrice)) (rupiah .GrossSubtotal)}}
+{{- range .Components}}
+{{wrap (printf "  - %d x %s" .Quantity .ProductName)}}
+{{- end}}
+{{- if .DiscountAmount}}
+{{row "  Diskon" (printf "-%s" (rupiah .DiscountAmount))}}
+{{- end}}
+{{- end}}
+{{line "-"}}
+{{- if .Transaction.DiscountAmount}}
+{{row "Total Diskon" (printf "-%s" (rupiah .Transaction.DiscountAmount))}}
+{{- end}}
+{{- if .Transaction.PointsDiscount}}
+{{row (printf "  Tukar %d poin" .Transaction.PointsRedeemed) (printf "-%s" (rupiah .Transaction.PointsDiscount))}}
+{{- end}}
+{{row "Subtotal" (rupiah .Transaction.SubtotalAmount)}}
+{{- if .Transaction.TaxAmount}}
+{{row "Pajak" (rupiah .Transaction.TaxAmount)}}
+{{- end}}
+{{- if .Transaction.ServiceChargeAmount}}
+{{row "Service Charge" (rupiah .Transaction.ServiceChargeAmount)}}
+{{- end}}
+{{bold (row "TOTAL" (rupiah .Transaction.TotalAmount))}}
+{{line "-"}}
+{{- range .Transaction.Payments}}
+{{row (paymentLabel .Method) (rupiah .Tendered)}}
+{{- end}}
+{{row "Kembali" (rupiah .Transaction.ChangeAmount)}}
+{{- if .Transaction.PointsEarned}}
+{{row "Poin didapat" (printf "%d" .Transaction.PointsEarned)}}
+{{- end}}
+{{- range .Transaction.Refunds}}
+{{row (printf "Refund #%d" .ID) (printf "-%s" (rupiah .TotalAmount))}}
+{{- end}}
+{{line "="}}
+{{- if .Store.Footer}}
+{{center .Store.Footer}}
+{{- end}}
+`
+
+const defaultReceiptHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Struk #{{.Transaction.ID}}</title>
+<style>
+  body { font-family: monospace; width: 58mm; margin: 0 auto; font-size: 12px; }
+  h1 { font-size: 14px; text-align: center; margin: 0; }
+  .center { text-align: center; }
+  table { width: 100%; border-collapse: collapse; }
+  td.amount { text-align: right; }
+  .total td { font-weight: bold; border-top: 1px dashed #000; }
+  hr { border: 0; border-top: 1px dashed #000; }
+</style>
+</head>
+<body>
+<h1>{{.Store.StoreName}}</h1>
+{{- if .Store.StoreAddress}}
+<div class="center">{{.Store.StoreAddress}}</div>
+{{- end}}
+{{- if .Store.StorePhone}}
+<div class="center">{{.Store.StorePhone}}</div>
+{{- end}}
+<hr>
+<table>
+  <tr><td>No. {{.Transaction.ID}}</td><td class="amount">{{date .Transaction.CreatedAt}}</td></tr>
+</table>
+{{- if eq .Transaction.Status "voided"}}
+<div class="center"><strong>*** VOID ***</strong></div>
+{{- end}}
+<hr>
+<table>
+{{- range .Transaction.Details}}
+  <tr><td colspan="2">{{.ProductName}}</td></tr>
+  <tr><td>&nbsp;&nbsp;{{quantity .}} x {{rupiah .UnitPrice}}</td><td class="amount">{{rupiah .GrossSubtotal}}</td></tr>
+  {{- range .Components}}
+  <tr><td colspan="2">&nbsp;&nbsp;- {{.Quantity}} x {{.ProductName}}</td></tr>
+  {{- end}}
+  {{- if .DiscountAmount}}
+  <tr><td>&nbsp;&nbsp;Diskon</td><td class="amount">-{{rupiah .DiscountAmount}}</td></tr>
+  {{- end}}
+{{- end}}
+</table>
+<hr>
+<table>
+  {{- if .Transaction.DiscountAmount}}
+  <tr><td>Total Diskon</td><td class="amount">-{{rupiah .Transaction.DiscountAmount}}</td></tr>
+  {{- end}}
+  {{- if .Transaction.PointsDiscount}}
+  <tr><td>&nbsp;&nbsp;Tukar {{.Transaction.PointsRedeemed}} poin</td><td class="amount">-{{rupiah .Transaction.PointsDiscount}}</td></tr>
+  {{- end}}
+  <tr><td>Subtotal</td><td class="amount">{{rupiah .Transaction.SubtotalAmount}}</td></tr>
+  {{- if .Transaction.TaxAmount}}
+  <tr><td>Pajak</td><td class="amount">{{rupiah .Transaction.TaxAmount}}</td></tr>
+  {{- end}}
+  {{- if .Transaction.ServiceChargeAmount}}
+  <tr><td>Service Charge</td><td class="amount">{{rupiah .Transaction.ServiceChargeAmount}}</td></tr>
+  {{- end}}
+  <tr class="total"><td>TOTAL</td><td class="amount">{{rupiah .Transaction.TotalAmount}}</td></tr>
+  {{- range .Transaction.Payments}}
+  <tr><td>{{paymentLabel .Method}}</td><td class="amount">{{rupiah .Tendered}}</td></tr>
+  {{- end}}
+  <tr><td>Kembali</td><td class="amount">{{rupiah .Transaction.ChangeAmount}}</td></tr>
+  {{- if .Transaction.PointsEarned}}
+  <tr><td>Poin didapat</td><td class="amount">{{.Transaction.PointsEarned}}</td></tr>
+  {{- end}}
+  {{- range .Transaction.Refunds}}
+  <tr><td>Refund #{{.ID}}</td><td class="amount">-{{rupiah .TotalAmount}}</td></tr>
+  {{- end}}
+</table>
+<hr>
+{{- if .Store.Footer}}
+<div class="center">{{.Store.Footer}}</div>
+{{- end}}
+</body>
+</html>
+`
@@ -14,12 +14,12 @@ func NewReportService(repo *repositories.ReportRepository) *ReportService {
 	return &ReportService{repo: repo}
 }
 
-// GetDailySales buat ambil laporan penjualan hari ini
-func (s *ReportService) GetDailySales() (*models.DailySalesReport, error) {
-	return s.repo.GetDailySales()
+// GetDailySales buat ambil laporan penjualan hari ini, outletID 0 berarti semua outlet
+func (s *ReportService) GetDailySales(outletID int) (*models.DailySalesReport, error) {
+	return s.repo.GetDailySales(outletID)
 }
 
-// GetReportByDateRange buat ambil laporan penjualan berdasarkan range tanggal
-func (s *ReportService) GetReportByDateRange(startDate, endDate string) (*models.DailySalesReport, error) {
-	return s.repo.GetReportByDateRange(startDate, endDate)
+// GetReportByDateRange buat ambil laporan penjualan berdasarkan range tanggal, outletID 0 berarti semua outlet
+func (s *ReportService) GetReportByDateRange(startDate, endDate string, outletID int) (*models.DailySalesReport, error) {
+	return s.repo.GetReportByDateRange(startDate, endDate, outletID)
 }
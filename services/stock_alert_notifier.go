@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+)
+
+// Jenis notifier low stock yang didukung
+const (
+	StockAlertNotifierLog     = "log"
+	StockAlertNotifierWebhook = "webhook"
+	StockAlertNotifierSMTP    = "smtp"
+)
+
+// StockAlertNotifier itu interface buat ngirim notifikasi low stock, tinggal implement
+// kalau mau nambah channel lain (misalnya Telegram atau Slack)
+type StockAlertNotifier interface {
+	NotifyLowStock(alert models.LowStockAlert) error
+}
+
+// StockAlertConfig itu konfigurasi checker low stock. Notifier isinya log, webhook atau smtp,
+// kosong berarti log. CheckInterval itu jeda pengecekan rutin dalam detik
+type StockAlertConfig struct {
+	Notifier      string
+	CheckInterval int
+	WebhookURL    string
+	SMTPAddr      string
+	SMTPUsername  string
+	SMTPPassword  string
+	SMTPFrom      string
+	SMTPTo        string
+}
+
+// NewStockAlertNotifier buat bikin notifier sesuai konfigurasi
+func NewStockAlertNotifier(config StockAlertConfig) (StockAlertNotifier, error) {
+	switch config.Notifier {
+	case StockAlertNotifierLog, "":
+		return LogNotifier{}, nil
+	case StockAlertNotifierWebhook:
+		if config.WebhookURL == "" {
+			return nil, errors.New("LOW_STOCK_WEBHOOK_URL is required for webhook notifier")
+		}
+		return &WebhookNotifier{URL: config.WebhookURL, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case StockAlertNotifierSMTP:
+		if config.SMTPAddr == "" || config.SMTPFrom == "" || config.SMTPTo == "" {
+			return nil, errors.New("SMTP_ADDR, SMTP_FROM and SMTP_TO are required for smtp notifier")
+		}
+		to := make([]string, 0)
+		for _, addr := range strings.Split(config.SMTPTo, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				to = append(to, addr)
+			}
+		}
+		return &SMTPNotifier{
+			Addr:     config.SMTPAddr,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.SMTPFrom,
+			To:       to,
+		}, nil
+	}
+	return nil, errors.New("low stock notifier must be log, webhook or smtp")
+}
+
+// LogNotifier itu notifier yang cuma nulis ke log aplikasi
+type LogNotifier struct{}
+
+// NotifyLowStock buat nulis alert ke log
+func (LogNotifier) NotifyLowStock(alert models.LowStockAlert) error {
+	log.Println(lowStockMessage(alert))
+	return nil
+}
+
+// WebhookNotifier itu notifier yang POST alert dalam bentuk JSON ke URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NotifyLowStock buat kirim alert ke webhook, status selain 2xx dianggap gagal
+func (n *WebhookNotifier) NotifyLowStock(alert models.LowStockAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.Client.Post(n.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier itu notifier yang kirim alert lewat email. Username boleh kosong
+// buat SMTP server lokal/testing (misalnya MailHog) yang nggak pakai auth
+type SMTPNotifier struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// NotifyLowStock buat kirim alert sebagai email plain text
+func (n *SMTPNotifier) NotifyLowStock(alert models.LowStockAlert) error {
+	var auth smtp.Auth
+	if n.Username != "" {
+		host, _, _ := strings.Cut(n.Addr, ":")
+		auth = smtp.PlainAuth("", n.Username, n.Password, host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: Stock menipis: %s\r\n", alert.ProductName)
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(lowStockMessage(alert) + "\r\n")
+
+	return smtp.SendMail(n.Addr, auth, n.From, n.To, []byte(msg.String()))
+}
+
+// lowStockMessage buat bikin pesan alert yang bisa dibaca manusia
+func lowStockMessage(alert models.LowStockAlert) string {
+	name := alert.ProductName
+	if alert.SKU != "" {
+		name += " (" + alert.SKU + ")"
+	}
+	return fmt.Sprintf("Stock menipis: %s tinggal %d, minimum %d. Saran pesan ulang %d. Pemicu: %s #%d",
+		name, alert.Stock, alert.MinStock, alert.SuggestedOrderQuantity, alert.Reason, alert.ReferenceID)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+// StockAlertService itu checker low stock yang jalan di background. Alert-nya diambil dari outbox yang diisi
+// tiap pergerakan stock, jadi semua sumber pengurangan stock (checkout, adjustment, opname) ikut kepantau
+type StockAlertService struct {
+	repo     *repositories.StockMovementRepository
+	notifier StockAlertNotifier
+	interval time.Duration
+	trigger  chan struct{}
+}
+
+// NewStockAlertService buat bikin instance service baru, interval dalam detik (default 60)
+func NewStockAlertService(repo *repositories.StockMovementRepository, notifier StockAlertNotifier, interval int) *StockAlertService {
+	if interval <= 0 {
+		interval = 60
+	}
+	return &StockAlertService{
+		repo:     repo,
+		notifier: notifier,
+		interval: time.Duration(interval) * time.Second,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// Trigger buat minta checker langsung ngecek tanpa nunggu interval. Nggak pernah nge-block,
+// kalau udah ada trigger yang antri yang ini digabung aja
+func (s *StockAlertService) Trigger() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run buat jalanin checker sampai ctx selesai, panggil pakai goroutine.
+// Alert yang belum terkirim waktu server mati dikirim begitu checker jalan lagi
+func (s *StockAlertService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.check()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+	}
+}
+
+// check buat ngirim alert yang belum terkirim, yang berhasil ditandain di outbox. Alert yang gagal dikirim
+// di-log dan dicoba lagi di pengecekan berikutnya, alert lain tetap dikirim biar checker nggak macet
+func (s *StockAlertService) check() {
+	alerts, err := s.repo.GetPendingLowStockAlerts()
+	if err != nil {
+		log.Println("stock alert: gagal cek low stock:", err)
+		return
+	}
+
+	for _, alert := range alerts {
+		if err := s.notifier.NotifyLowStock(alert); err != nil {
+			log.Printf("stock alert: gagal kirim notifikasi product %d: %v", alert.ProductID, err)
+			continue
+		}
+		if err := s.repo.MarkLowStockAlertNotified(alert.MovementID); err != nil {
+			log.Printf("stock alert: gagal nandain alert product %d: %v", alert.ProductID, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type StockOpnameService struct {
+	repo *repositories.StockOpnameRepository
+}
+
+// NewStockOpnameService buat bikin instance service baru
+func NewStockOpnameService(repo *repositories.StockOpnameRepository) *StockOpnameService {
+	return &StockOpnameService{repo: repo}
+}
+
+// GetAll buat ambil semua sesi stock opname
+func (s *StockOpnameService) GetAll() ([]models.StockOpname, error) {
+	return s.repo.GetAll()
+}
+
+// GetByID buat ambil sesi stock opname by ID beserta hasil hitungnya
+func (s *StockOpnameService) GetByID(id int) (*models.StockOpname, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat buka sesi stock opname baru
+func (s *StockOpnameService) Create(opname *models.StockOpname) error {
+	opname.Name = strings.TrimSpace(opname.Name)
+	opname.CreatedBy = strings.TrimSpace(opname.CreatedBy)
+	if opname.Name == "" {
+		return errors.New("name is required")
+	}
+	if opname.CreatedBy == "" {
+		return errors.New("created_by is required")
+	}
+	return s.repo.Create(opname)
+}
+
+// SubmitCounts buat nyimpen hitungan fisik dari satu device, boleh dikirim sebagian-sebagian
+func (s *StockOpnameService) SubmitCounts(id int, req models.StockOpnameCountRequest) error {
+	req.CountedBy = strings.TrimSpace(req.CountedBy)
+	if req.CountedBy == "" {
+		return errors.New("counted_by is required")
+	}
+	if len(req.Items) == 0 {
+		return errors.New("items cannot be empty")
+	}
+
+	seen := make(map[int]bool)
+	for _, item := range req.Items {
+		if item.CountedQuantity < 0 {
+			return fmt.Errorf("counted_quantity for product id %d cannot be negative", item.ProductID)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("product id %d is counted more than once", item.ProductID)
+		}
+		seen[item.ProductID] = true
+	}
+
+	return s.repo.SubmitCounts(id, req)
+}
+
+// GetVariances buat ambil laporan selisih stock sesi stock opname, dinilai pakai harga produk
+func (s *StockOpnameService) GetVariances(id int) (*models.StockOpnameVarianceReport, error) {
+	status, variances, err := s.repo.GetVariances(id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.StockOpnameVarianceReport{StockOpnameID: id, Status: status, Items: variances}
+	for _, v := range variances {
+		if v.VarianceValue > 0 {
+			report.TotalSurplusValue += v.VarianceValue
+		} else {
+			report.TotalShortageValue -= v.VarianceValue
+		}
+		report.NetVarianceValue += v.VarianceValue
+	}
+
+	return report, nil
+}
+
+// Finalize buat nutup sesi stock opname dan posting semua selisihnya ke stock
+func (s *StockOpnameService) Finalize(id int, req models.StockOpnameFinalizeRequest) (*models.StockOpname, error) {
+	req.FinalizedBy = strings.TrimSpace(req.FinalizedBy)
+	if req.FinalizedBy == "" {
+		return nil, errors.New("finalized_by is required")
+	}
+	return s.repo.Finalize(id, req.FinalizedBy)
+}
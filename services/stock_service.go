@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type StockService struct {
+	movementRepo   *repositories.StockMovementRepository
+	adjustmentRepo *repositories.StockAdjustmentRepository
+	stockAlerts    *StockAlertService
+}
+
+// NewStockService buat bikin instance service baru
+func NewStockService(movementRepo *repositories.StockMovementRepository, adjustmentRepo *repositories.StockAdjustmentRepository, stockAlerts *StockAlertService) *StockService {
+	return &StockService{movementRepo: movementRepo, adjustmentRepo: adjustmentRepo, stockAlerts: stockAlerts}
+}
+
+// GetMovements buat ambil ledger stock satu produk sekalian rekonsiliasi stock sekarang vs jumlah ledger
+func (s *StockService) GetMovements(productID, page, limit int) (*models.StockMovementList, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	stock, ledgerStock, err := s.movementRepo.GetBalance(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, total, err := s.movementRepo.GetByProductID(productID, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockMovementList{
+		ProductID:   productID,
+		Stock:       stock,
+		LedgerStock: ledgerStock,
+		Difference:  stock - ledgerStock,
+		Data:        movements,
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+	}, nil
+}
+
+// Adjust buat bikin stock adjustment, stock produk langsung berubah dan tercatat di ledger
+func (s *StockService) Adjust(adjustment *models.StockAdjustment) error {
+	if err := validateStockAdjustment(adjustment); err != nil {
+		return err
+	}
+	if err := s.adjustmentRepo.Create(adjustment); err != nil {
+		return err
+	}
+	if adjustment.Quantity < 0 {
+		s.stockAlerts.Trigger()
+	}
+	return nil
+}
+
+// GetLowStock buat ambil produk yang stock-nya udah di bawah atau sama dengan batas minimum
+func (s *StockService) GetLowStock() ([]models.LowStockProduct, error) {
+	return s.movementRepo.GetLowStock()
+}
+
+// GetLots buat ambil lot produk track_lots yang masih ada stock-nya, outletID 0 berarti semua outlet
+func (s *StockService) GetLots(productID, outletID int) ([]models.StockLot, error) {
+	return s.movementRepo.GetLots(productID, outletID)
+}
+
+// GetExpiring buat laporan lot yang udah kadaluarsa atau kadaluarsa dalam withinDays hari ke depan,
+// outletID 0 berarti semua outlet
+func (s *StockService) GetExpiring(withinDays, outletID int) ([]models.ExpiringLot, error) {
+	if withinDays < 0 {
+		return nil, errors.New("within cannot be negative")
+	}
+	return s.movementRepo.GetExpiring(withinDays, outletID)
+}
+
+// normalizeLotInput buat ngerapiin batch number dan ngecek format tanggal kadaluarsa (YYYY-MM-DD)
+func normalizeLotInput(batchNumber, expiryDate *string) error {
+	*batchNumber = strings.TrimSpace(*batchNumber)
+	if len(*batchNumber) > 64 {
+		return errors.New("batch_number must be at most 64 characters")
+	}
+	*expiryDate = strings.TrimSpace(*expiryDate)
+	if *expiryDate != "" {
+		if _, err := time.Parse("2006-01-02", *expiryDate); err != nil {
+			return errors.New("expiry_date must use format YYYY-MM-DD")
+		}
+	}
+	return nil
+}
+
+// validateStockAdjustment buat ngecek arah quantity sesuai alasan adjustment-nya
+func validateStockAdjustment(a *models.StockAdjustment) error {
+	a.Note = strings.TrimSpace(a.Note)
+	a.CreatedBy = strings.TrimSpace(a.CreatedBy)
+	if err := normalizeLotInput(&a.BatchNumber, &a.ExpiryDate); err != nil {
+		return err
+	}
+	if a.LotID < 0 {
+		return errors.New("lot_id cannot be negative")
+	}
+	if a.OutletID < 0 {
+		return errors.New("outlet_id cannot be negative")
+	}
+	if a.CreatedBy == "" {
+		return errors.New("created_by is required")
+	}
+	if a.Quantity == 0 {
+		return errors.New("quantity cannot be 0")
+	}
+
+	switch a.Reason {
+	case models.AdjustmentReasonDamaged, models.AdjustmentReasonLost, models.AdjustmentReasonExpired:
+		if a.Quantity > 0 {
+			return errors.New("quantity must be negative for reason " + a.Reason)
+		}
+	case models.AdjustmentReasonFound:
+		if a.Quantity < 0 {
+			return errors.New("quantity must be positive for reason found")
+		}
+	case models.AdjustmentReasonCorrection:
+		if a.Note == "" {
+			return errors.New("note is required for reason correction")
+		}
+	default:
+		return errors.New("reason must be damaged, lost, expired, found or correction")
+	}
+
+	return nil
+}
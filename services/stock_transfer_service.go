@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type StockTransferService struct {
+	repo *repositories.StockTransferRepository
+}
+
+// NewStockTransferService buat bikin instance service baru
+func NewStockTransferService(repo *repositories.StockTransferRepository) *StockTransferService {
+	return &StockTransferService{repo: repo}
+}
+
+// GetAll buat ambil semua transfer stock, status kosong berarti semua status
+func (s *StockTransferService) GetAll(status string) ([]models.StockTransfer, error) {
+	return s.repo.GetAll(status)
+}
+
+// GetByID buat ambil transfer stock by ID
+func (s *StockTransferService) GetByID(id int) (*models.StockTransfer, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin permintaan transfer stock antar outlet
+func (s *StockTransferService) Create(t *models.StockTransfer) error {
+	if err := validateStockTransfer(t); err != nil {
+		return err
+	}
+	if err := s.repo.Create(t); err != nil {
+		return err
+	}
+	saved, err := s.repo.GetByID(t.ID)
+	if err != nil {
+		return err
+	}
+	*t = *saved
+	return nil
+}
+
+// Ship buat ngirim transfer, stock outlet asal langsung pindah ke lokasi transit. Stock total produknya
+// nggak berubah jadi nggak ada alert stock menipis
+func (s *StockTransferService) Ship(id int, req models.StockTransferActionRequest) (*models.StockTransfer, error) {
+	by := strings.TrimSpace(req.By)
+	if by == "" {
+		return nil, errors.New("by is required")
+	}
+	if err := s.repo.Ship(id, by); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Receive buat nerima transfer di outlet tujuan
+func (s *StockTransferService) Receive(id int, req models.StockTransferActionRequest) (*models.StockTransfer, error) {
+	by := strings.TrimSpace(req.By)
+	if by == "" {
+		return nil, errors.New("by is required")
+	}
+	if err := s.repo.Receive(id, by); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Cancel buat batalin transfer yang belum dikirim
+func (s *StockTransferService) Cancel(id int) (*models.StockTransfer, error) {
+	if err := s.repo.Cancel(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// validateStockTransfer buat ngecek outlet asal dan tujuan beda dan item-nya valid, satu produk cuma boleh satu baris
+func validateStockTransfer(t *models.StockTransfer) error {
+	t.Note = strings.TrimSpace(t.Note)
+	t.RequestedBy = strings.TrimSpace(t.RequestedBy)
+	if t.FromOutletID <= 0 {
+		return errors.New("from_outlet_id is required")
+	}
+	if t.ToOutletID <= 0 {
+		return errors.New("to_outlet_id is required")
+	}
+	if t.FromOutletID == t.ToOutletID {
+		return errors.New("from_outlet_id and to_outlet_id must be different")
+	}
+	if t.RequestedBy == "" {
+		return errors.New("requested_by is required")
+	}
+	if len(t.Items) == 0 {
+		return errors.New("items cannot be empty")
+	}
+
+	seen := make(map[int]bool)
+	for _, item := range t.Items {
+		if item.ProductID == 0 {
+			return errors.New("product_id is required")
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("product id %d is listed more than once", item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.Quantity <= 0 {
+			return fmt.Errorf("quantity for product id %d must be greater than 0", item.ProductID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
+	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
+)
+
+type TaxService struct {
+	repo              *repositories.TaxRateRepository
+	serviceChargeRate float64
+}
+
+// NewTaxService buat bikin instance service baru. serviceChargeRate dalam persen, 0 berarti tanpa service charge
+func NewTaxService(repo *repositories.TaxRateRepository, serviceChargeRate float64) *TaxService {
+	return &TaxService{repo: repo, serviceChargeRate: serviceChargeRate}
+}
+
+// GetAll buat ambil semua tax rates
+func (s *TaxService) GetAll() ([]models.TaxRate, error) {
+	return s.repo.GetAll()
+}
+
+// GetByID buat ambil tax rate by ID
+func (s *TaxService) GetByID(id int) (*models.TaxRate, error) {
+	return s.repo.GetByID(id)
+}
+
+// Create buat bikin tax rate baru
+func (s *TaxService) Create(taxRate *models.TaxRate) error {
+	if err := validateTaxRate(taxRate); err != nil {
+		return err
+	}
+	return s.repo.Create(taxRate)
+}
+
+// Update buat update tax rate
+func (s *TaxService) Update(taxRate *models.TaxRate) error {
+	if err := validateTaxRate(taxRate); err != nil {
+		return err
+	}
+	return s.repo.Update(taxRate)
+}
+
+// Delete buat hapus tax rate
+func (s *TaxService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validateTaxRate(t *models.TaxRate) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if t.Rate < 0 || t.Rate > 100 {
+		return errors.New("rate must be between 0 and 100")
+	}
+	return nil
+}
+
+// ApplyTaxes buat ngitung pajak per baris dan service charge setelah diskon.
+// Tarif per baris diambil dari produk, lalu kategori (udah di-resolve di TaxRateID), lalu tarif default.
+// Service charge dihitung dari subtotal tanpa pajak dan dibagi proporsional ke tiap baris
+func (s *TaxService) ApplyTaxes(t *models.Transaction) error {
+	taxRates, err := s.repo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	ratesByID := make(map[int]models.TaxRate)
+	var defaultRate *models.TaxRate
+	for i := range taxRates {
+		ratesByID[taxRates[i].ID] = taxRates[i]
+		if taxRates[i].IsDefault {
+			defaultRate = &taxRates[i]
+		}
+	}
+
+	t.SubtotalAmount, t.TaxAmount = 0, 0
+	exclusiveTax, netOfTax := 0, 0
+	for i := range t.Details {
+		d := &t.Details[i]
+		d.TaxRate, d.TaxInclusive, d.TaxAmount = 0, false, 0
+
+		rate, ok := ratesByID[d.TaxRateID]
+		if !ok && defaultRate != nil {
+			rate, ok = *defaultRate, true
+		}
+		if d.TaxExempt || !ok {
+			d.TaxRateID = 0
+		} else {
+			d.TaxRateID = rate.ID
+			d.TaxRate = rate.Rate
+			d.TaxInclusive = rate.Inclusive
+			if rate.Inclusive {
+				d.TaxAmount = int(math.Round(float64(d.Subtotal) * rate.Rate / (100 + rate.Rate)))
+			} else {
+				d.TaxAmount = int(math.Round(float64(d.Subtotal) * rate.Rate / 100))
+				exclusiveTax += d.TaxAmount
+			}
+		}
+
+		t.SubtotalAmount += d.Subtotal
+		t.TaxAmount += d.TaxAmount
+		netOfTax += lineNetOfTax(d)
+	}
+
+	t.ServiceChargeAmount = int(math.Round(float64(netOfTax) * s.serviceChargeRate / 100))
+	allocateServiceCharge(t.Details, t.ServiceChargeAmount)
+
+	for i := range t.Details {
+		d := &t.Details[i]
+		d.Total = d.Subtotal + d.ServiceChargeAmount
+		if !d.TaxInclusive {
+			d.Total += d.TaxAmount
+		}
+	}
+	t.TotalAmount = t.SubtotalAmount + exclusiveTax + t.ServiceChargeAmount
+
+	return nil
+}
+
+// lineNetOfTax buat ambil subtotal baris tanpa pajak, dasar perhitungan service charge.
+// Pajak inclusive udah ada di dalam subtotal jadi dikurangi dulu
+func lineNetOfTax(d *models.TransactionDetail) int {
+	if d.TaxInclusive {
+		return d.Subtotal - d.TaxAmount
+	}
+	return d.Subtotal
+}
+
+// allocateServiceCharge buat bagi service charge ke tiap baris sesuai proporsi subtotal tanpa pajak,
+// dasar yang sama dengan waktu service charge-nya dihitung. Sisa pembulatan masuk ke baris terakhir
+func allocateServiceCharge(details []models.TransactionDetail, serviceCharge int) {
+	if len(details) == 0 {
+		return
+	}
+	for i := range details {
+		details[i].ServiceChargeAmount = 0
+	}
+
+	netOfTax := 0
+	for i := range details {
+		netOfTax += lineNetOfTax(&details[i])
+	}
+	if netOfTax == 0 {
+		return
+	}
+
+	remaining := serviceCharge
+	for i := range details {
+		details[i].ServiceChargeAmount = serviceCharge * lineNetOfTax(&details[i]) / netOfTax
+		remaining -= details[i].ServiceChargeAmount
+	}
+	details[len(details)-1].ServiceChargeAmount += remaining
+}
@@ -1,20 +1,198 @@
 package services
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/models"
 	"github.com/achmadhadikurnia/bootcamp-jago-golang-dasar/repositories"
 )
 
 type TransactionService struct {
-	repo *repositories.TransactionRepository
+	repo             *repositories.TransactionRepository
+	refundRepo       *repositories.RefundRepository
+	promotionService *PromotionService
+	taxService       *TaxService
+	stockAlerts      *StockAlertService
+	loyalty          *LoyaltyService
 }
 
 // NewTransactionService buat bikin instance service baru
-func NewTransactionService(repo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{repo: repo}
+func NewTransactionService(repo *repositories.TransactionRepository, refundRepo *repositories.RefundRepository, promotionService *PromotionService, taxService *TaxService, stockAlerts *StockAlertService, loyalty *LoyaltyService) *TransactionService {
+	return &TransactionService{repo: repo, refundRepo: refundRepo, promotionService: promotionService, taxService: taxService, stockAlerts: stockAlerts, loyalty: loyalty}
+}
+
+// ErrIdempotencyKeyMismatch dikembalikan kalau Idempotency-Key dipakai ulang dengan body yang beda
+var ErrIdempotencyKeyMismatch = errors.New("idempotency key was already used with a different request body")
+
+// ErrExpiredStock dikembalikan kalau checkout cuma bisa dipenuhi dari lot yang udah kadaluarsa
+var ErrExpiredStock = repositories.ErrExpiredStock
+
+// ErrTransactionNotFound dikembalikan kalau transaksi dengan ID itu nggak ada
+var ErrTransactionNotFound = repositories.ErrTransactionNotFound
+
+// Checkout buat proses checkout items dan pembayarannya. Kalau request bawa Idempotency-Key yang udah
+// pernah sukses, transaksi aslinya dikembalikan lagi (replayed = true) tanpa bikin transaksi baru
+func (s *TransactionService) Checkout(req models.CheckoutRequest) (transaction *models.Transaction, replayed bool, err error) {
+	defer func() {
+		// Checkout baru ngurangin stock, minta checker low stock langsung ngecek
+		if err == nil && !replayed {
+			s.stockAlerts.Trigger()
+		}
+	}()
+
+	if err := normalizeCheckoutBarcodes(req.Items); err != nil {
+		return nil, false, err
+	}
+	for i := range req.Payments {
+		if req.Payments[i].Method == models.PaymentMethodGiftCard {
+			req.Payments[i].GiftCardCode = normalizeGiftCardCode(req.Payments[i].GiftCardCode)
+		}
+	}
+	req.PointsExpiryDays = s.loyalty.ExpiryDays()
+
+	if req.IdempotencyKey == "" {
+		transaction, err = s.repo.CreateTransaction(req, s.price)
+		return transaction, false, err
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, false, err
+	}
+	hash := sha256.Sum256(body)
+	req.RequestHash = hex.EncodeToString(hash[:])
+
+	transaction, replayed, err = s.replayCheckout(req)
+	if replayed || err != nil {
+		return transaction, replayed, err
+	}
+
+	transaction, err = s.repo.CreateTransaction(req, s.price)
+	if errors.Is(err, repositories.ErrIdempotencyKeyExists) {
+		// Kalah balapan sama request lain yang bawa key sama, ambil hasil dari request itu
+		return s.replayCheckout(req)
+	}
+	return transaction, false, err
+}
+
+// normalizeCheckoutBarcodes buat validasi barcode item checkout dan ngerapiin nama satuannya. Produknya dicari di repository
+// di dalam DB transaction yang sama dengan checkout-nya
+func normalizeCheckoutBarcodes(items []models.CheckoutItem) error {
+	for i := range items {
+		item := &items[i]
+		item.Unit = strings.TrimSpace(item.Unit)
+		if item.Barcode == "" {
+			continue
+		}
+		if item.ProductID != 0 {
+			return errors.New("checkout item must use either product_id or barcode, not both")
+		}
+		barcode, err := normalizeBarcode(item.Barcode)
+		if err != nil {
+			return err
+		}
+		item.Barcode = barcode
+	}
+	return nil
+}
+
+// price buat ngitung harga akhir checkout, dipanggil repository setelah produk di-lock.
+// Diskon promo dan tukar poin dihitung duluan, pajak dan service charge dihitung dari harga setelah diskon,
+// poin member dihitung dari total yang dibayar
+func (s *TransactionService) price(transaction *models.Transaction) error {
+	if err := s.promotionService.ApplyPromotions(transaction); err != nil {
+		return err
+	}
+	if err := s.loyalty.RedeemPoints(transaction); err != nil {
+		return err
+	}
+	if err := s.taxService.ApplyTaxes(transaction); err != nil {
+		return err
+	}
+	s.loyalty.EarnPoints(transaction)
+	return nil
 }
 
-// Checkout buat proses checkout items
-func (s *TransactionService) Checkout(items []models.CheckoutItem) (*models.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+// replayCheckout buat ngembaliin response checkout yang udah tersimpan untuk Idempotency-Key ini
+func (s *TransactionService) replayCheckout(req models.CheckoutRequest) (*models.Transaction, bool, error) {
+	stored, err := s.repo.GetIdempotencyKey(req.IdempotencyKey)
+	if err != nil || stored == nil {
+		return nil, false, err
+	}
+	if stored.RequestHash != req.RequestHash {
+		return nil, false, ErrIdempotencyKeyMismatch
+	}
+
+	var transaction models.Transaction
+	err = json.Unmarshal(stored.ResponseBody, &transaction)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &transaction, true, nil
+}
+
+// GetAll buat ambil riwayat transaksi, sekalian pasang default pagination
+func (s *TransactionService) GetAll(filter models.TransactionFilter) (*models.TransactionList, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+
+	transactions, total, err := s.repo.GetAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionList{
+		Data:  transactions,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+// GetByID buat ambil transaksi by ID beserta dokumen refund-nya
+func (s *TransactionService) GetByID(id int) (*models.Transaction, error) {
+	transaction, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Refunds, err = s.refundRepo.GetByTransactionID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Void buat batalin seluruh transaksi, semua sisa item dikembalikan ke stock
+func (s *TransactionService) Void(id int, reason string) (*models.Refund, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	return s.refundRepo.CreateRefund(id, models.RefundTypeVoid, models.RefundRequest{Reason: reason, PointsExpiryDays: s.loyalty.ExpiryDays()})
+}
+
+// Refund buat refund transaksi, full kalau items kosong atau sebagian per baris
+func (s *TransactionService) Refund(id int, req models.RefundRequest) (*models.Refund, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	req.PointsExpiryDays = s.loyalty.ExpiryDays()
+	return s.refundRepo.CreateRefund(id, models.RefundTypeRefund, req)
 }